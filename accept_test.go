@@ -0,0 +1,64 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	offers := []string{"text/plain", "application/openmetrics-text"}
+
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{
+			name:   "no header",
+			accept: "",
+			want:   "text/plain",
+		},
+		{
+			name:   "wildcard prefers first offer",
+			accept: "*/*",
+			want:   "text/plain",
+		},
+		{
+			name:   "exact match",
+			accept: "application/openmetrics-text; version=1.0.0",
+			want:   "application/openmetrics-text",
+		},
+		{
+			name:   "prometheus scrape header",
+			accept: "application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5,*/*;q=0.1",
+			want:   "application/openmetrics-text",
+		},
+		{
+			name:   "quality values",
+			accept: "application/openmetrics-text;q=0.2, text/plain;q=0.9",
+			want:   "text/plain",
+		},
+		{
+			name:   "subtype wildcard",
+			accept: "application/*",
+			want:   "application/openmetrics-text",
+		},
+		{
+			name:   "specific range overrides wildcard",
+			accept: "*/*;q=0.8, text/plain;q=0",
+			want:   "application/openmetrics-text",
+		},
+		{
+			name:   "nothing acceptable",
+			accept: "application/json",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateContentType(tt.accept, offers))
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package health
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Authenticator decides whether a request may access a protected endpoint.
+type Authenticator interface {
+	Authenticate(r *http.Request) bool
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface.
+type AuthenticatorFunc func(r *http.Request) bool
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) bool {
+	return f(r)
+}
+
+// challenger is implemented by authenticators that tell unauthenticated
+// clients how to authenticate in a WWW-Authenticate header.
+type challenger interface {
+	challenges() []string
+}
+
+const authRealm = `realm="health"`
+
+type bearerAuth struct {
+	tokens []string
+}
+
+// BearerToken accepts requests with an "Authorization: Bearer <token>" header
+// carrying one of tokens. Empty tokens are ignored, so a token read from an
+// unset environment variable never matches; with no tokens left, every
+// request is rejected.
+func BearerToken(tokens ...string) Authenticator {
+	valid := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if t != "" {
+			valid = append(valid, t)
+		}
+	}
+	return &bearerAuth{tokens: valid}
+}
+
+func (a *bearerAuth) Authenticate(r *http.Request) bool {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return false
+	}
+
+	valid := false
+	for _, t := range a.tokens {
+		if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+func (a *bearerAuth) challenges() []string {
+	return []string{"Bearer " + authRealm}
+}
+
+type basicAuth struct {
+	users map[string]string
+}
+
+// BasicAuth accepts requests with HTTP basic auth credentials matching one of
+// users, which maps user names to passwords.
+func BasicAuth(users map[string]string) Authenticator {
+	return &basicAuth{users: users}
+}
+
+func (a *basicAuth) Authenticate(r *http.Request) bool {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	want, known := a.users[user]
+	match := subtle.ConstantTimeCompare([]byte(want), []byte(password)) == 1
+	return known && match
+}
+
+func (a *basicAuth) challenges() []string {
+	return []string{"Basic " + authRealm}
+}
+
+type clientCertAuth struct {
+	names []string
+}
+
+// ClientCertificate accepts requests over TLS with a client certificate that
+// the server verified, i.e. mTLS. If names are given, the certificate's
+// common name, a DNS name or a URI SAN such as a SPIFFE ID must be one of
+// them. The server's tls.Config must request and verify client
+// certificates, e.g. with ClientAuth set to tls.VerifyClientCertIfGiven.
+func ClientCertificate(names ...string) Authenticator {
+	return &clientCertAuth{names: names}
+}
+
+func (a *clientCertAuth) Authenticate(r *http.Request) bool {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return false
+	}
+	if len(a.names) == 0 {
+		return true
+	}
+
+	leaf := r.TLS.VerifiedChains[0][0]
+	identities := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+	for _, uri := range leaf.URIs {
+		identities = append(identities, uri.String())
+	}
+
+	for _, name := range a.names {
+		for _, identity := range identities {
+			if identity != "" && identity == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type trustedNetworks struct {
+	prefixes []netip.Prefix
+}
+
+// TrustedNetworks accepts requests whose remote address is in one of
+// prefixes, e.g. netip.MustParsePrefix("10.0.0.0/8"). It trusts the address
+// of the connection, so behind a proxy it only sees the proxy.
+func TrustedNetworks(prefixes ...netip.Prefix) Authenticator {
+	return &trustedNetworks{prefixes: prefixes}
+}
+
+func (a *trustedNetworks) Authenticate(r *http.Request) bool {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+type anyAuth struct {
+	auths []Authenticator
+}
+
+// AnyOf accepts requests accepted by any of auths, e.g. a bearer token for
+// humans or a client certificate for other services.
+func AnyOf(auths ...Authenticator) Authenticator {
+	return &anyAuth{auths: auths}
+}
+
+func (a *anyAuth) Authenticate(r *http.Request) bool {
+	for _, auth := range a.auths {
+		if auth.Authenticate(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *anyAuth) challenges() []string {
+	var challenges []string
+	for _, auth := range a.auths {
+		if c, ok := auth.(challenger); ok {
+			challenges = append(challenges, c.challenges()...)
+		}
+	}
+	return challenges
+}
+
+// Endpoint identifies an endpoint of HTTPHandler by its default path,
+// regardless of the path it is served on.
+type Endpoint string
+
+const (
+	EndpointHealth    Endpoint = defaultHealthPath
+	EndpointLiveness  Endpoint = defaultLivenessPath
+	EndpointReadiness Endpoint = defaultReadinessPath
+	EndpointStartup   Endpoint = defaultStartupPath
+	EndpointStatus    Endpoint = defaultStatusPath
+	EndpointMetrics   Endpoint = defaultMetricsPath
+)
+
+// WithAuthentication requires requests to the given endpoints to be accepted
+// by auth and answers others with 401 Unauthorized. Without endpoints, only
+// EndpointStatus and EndpointMetrics are protected, so that kubelets, which
+// cannot authenticate, can keep probing the health endpoints.
+func WithAuthentication(auth Authenticator, endpoints ...Endpoint) HandlerOption {
+	return func(h *HTTPHandler) {
+		if len(endpoints) == 0 {
+			endpoints = []Endpoint{EndpointStatus, EndpointMetrics}
+		}
+
+		h.auth = auth
+		h.protected = make(map[string]bool, len(endpoints))
+		for _, e := range endpoints {
+			h.protected[string(e)] = true
+		}
+	}
+}
+
+// requireAuth wraps next so that it only serves authenticated requests.
+func (h *HTTPHandler) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.auth.Authenticate(r) {
+			next(w, r)
+			return
+		}
+
+		if c, ok := h.auth.(challenger); ok {
+			for _, challenge := range c.challenges() {
+				w.Header().Add("WWW-Authenticate", challenge)
+			}
+		}
+		h.writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+	}
+}
+
+// WithBearerToken makes the client send token as a bearer token.
+func WithBearerToken(token string) ClientOption {
+	return WithTokenSource(func(ctx context.Context) (string, error) {
+		return token, nil
+	})
+}
+
+// WithTokenSource makes the client send the token returned by source as a
+// bearer token. source is called for every request, so it can refresh
+// expiring tokens; it should cache them itself.
+func WithTokenSource(source func(ctx context.Context) (string, error)) ClientOption {
+	return func(c *client) {
+		c.tokenSource = source
+		c.basicAuth = nil
+	}
+}
+
+// WithBasicAuth makes the client send HTTP basic auth credentials.
+func WithBasicAuth(user, password string) ClientOption {
+	return func(c *client) {
+		c.basicAuth = &[2]string{user, password}
+		c.tokenSource = nil
+	}
+}
+
+// WithClientCertificates makes the client present certs to servers that
+// request a client certificate, for mTLS. It configures a copy of the
+// transport of the HTTP client, which must be an *http.Transport.
+func WithClientCertificates(certs ...tls.Certificate) ClientOption {
+	return func(c *client) {
+		c.certificates = append(c.certificates, certs...)
+	}
+}
+
+// authorize adds the configured credentials to req.
+func (c *client) authorize(req *http.Request) error {
+	switch {
+	case c.tokenSource != nil:
+		token, err := c.tokenSource(req.Context())
+		if err != nil {
+			return fmt.Errorf("getting token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.basicAuth != nil:
+		req.SetBasicAuth(c.basicAuth[0], c.basicAuth[1])
+	}
+	return nil
+}
+
+// configureCertificates replaces the HTTP client with a copy whose transport
+// presents the configured client certificates.
+func (c *client) configureCertificates() error {
+	if len(c.certificates) == 0 {
+		return nil
+	}
+
+	rt := c.httpClient.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("client certificates require an *http.Transport, got %T", rt)
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.Certificates = append(transport.TLSClientConfig.Certificates, c.certificates...)
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+	return nil
+}
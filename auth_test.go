@@ -0,0 +1,383 @@
+package health
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticators(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/monitor")
+	verified := func(cert *x509.Certificate) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	}
+
+	tests := []struct {
+		name  string
+		auth  Authenticator
+		setup func(r *http.Request)
+		want  bool
+	}{
+		{
+			name:  "bearer token",
+			auth:  BearerToken("old", "secret"),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			want:  true,
+		},
+		{
+			name:  "bearer token wrong token",
+			auth:  BearerToken("secret"),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") },
+			want:  false,
+		},
+		{
+			name:  "bearer token wrong scheme",
+			auth:  BearerToken("secret"),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Token secret") },
+			want:  false,
+		},
+		{
+			name:  "bearer token missing",
+			auth:  BearerToken("secret"),
+			setup: func(r *http.Request) {},
+			want:  false,
+		},
+		{
+			name:  "bearer token empty",
+			auth:  BearerToken(""),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer ") },
+			want:  false,
+		},
+		{
+			name:  "bearer token empty ignored",
+			auth:  BearerToken("", "secret"),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			want:  true,
+		},
+		{
+			name:  "basic auth",
+			auth:  BasicAuth(map[string]string{"sre": "hunter2"}),
+			setup: func(r *http.Request) { r.SetBasicAuth("sre", "hunter2") },
+			want:  true,
+		},
+		{
+			name:  "basic auth wrong password",
+			auth:  BasicAuth(map[string]string{"sre": "hunter2"}),
+			setup: func(r *http.Request) { r.SetBasicAuth("sre", "hunter3") },
+			want:  false,
+		},
+		{
+			name:  "basic auth unknown user",
+			auth:  BasicAuth(map[string]string{"sre": "hunter2"}),
+			setup: func(r *http.Request) { r.SetBasicAuth("guest", "") },
+			want:  false,
+		},
+		{
+			name:  "client certificate",
+			auth:  ClientCertificate(),
+			setup: func(r *http.Request) { r.TLS = verified(&x509.Certificate{}) },
+			want:  true,
+		},
+		{
+			name:  "client certificate without TLS",
+			auth:  ClientCertificate(),
+			setup: func(r *http.Request) {},
+			want:  false,
+		},
+		{
+			name:  "client certificate not verified",
+			auth:  ClientCertificate(),
+			setup: func(r *http.Request) { r.TLS = &tls.ConnectionState{} },
+			want:  false,
+		},
+		{
+			name: "client certificate common name",
+			auth: ClientCertificate("monitor"),
+			setup: func(r *http.Request) {
+				r.TLS = verified(&x509.Certificate{Subject: pkix.Name{CommonName: "monitor"}})
+			},
+			want: true,
+		},
+		{
+			name: "client certificate URI",
+			auth: ClientCertificate("spiffe://example.org/monitor"),
+			setup: func(r *http.Request) {
+				r.TLS = verified(&x509.Certificate{URIs: []*url.URL{spiffeID}})
+			},
+			want: true,
+		},
+		{
+			name: "client certificate other name",
+			auth: ClientCertificate("monitor"),
+			setup: func(r *http.Request) {
+				r.TLS = verified(&x509.Certificate{DNSNames: []string{"orders.internal"}})
+			},
+			want: false,
+		},
+		{
+			name:  "trusted network",
+			auth:  TrustedNetworks(netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")),
+			setup: func(r *http.Request) { r.RemoteAddr = "10.1.2.3:51234" },
+			want:  true,
+		},
+		{
+			name:  "trusted network IPv6",
+			auth:  TrustedNetworks(netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("fd00::/8")),
+			setup: func(r *http.Request) { r.RemoteAddr = "[fd00::1]:51234" },
+			want:  true,
+		},
+		{
+			name:  "untrusted network",
+			auth:  TrustedNetworks(netip.MustParsePrefix("10.0.0.0/8")),
+			setup: func(r *http.Request) { r.RemoteAddr = "203.0.113.7:51234" },
+			want:  false,
+		},
+		{
+			name:  "any of",
+			auth:  AnyOf(ClientCertificate(), BearerToken("secret")),
+			setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			want:  true,
+		},
+		{
+			name:  "func",
+			auth:  AuthenticatorFunc(func(r *http.Request) bool { return r.Header.Get("X-Internal") == "1" }),
+			setup: func(r *http.Request) { r.Header.Set("X-Internal", "1") },
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/status", nil)
+			tt.setup(r)
+			assert.Equal(t, tt.want, tt.auth.Authenticate(r))
+		})
+	}
+}
+
+func TestHTTPHandler_WithAuthentication(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpoints     []Endpoint
+		wantProtected map[string]bool
+	}{
+		{
+			name: "default endpoints",
+			wantProtected: map[string]bool{
+				"/health":          false,
+				"/health/live":     false,
+				"/health/ready":    false,
+				"/health/ready/db": false,
+				"/health/startup":  false,
+				"/status":          true,
+				"/metrics":         true,
+				"/readyz":          false,
+			},
+		},
+		{
+			name:      "selected endpoints",
+			endpoints: []Endpoint{EndpointHealth, EndpointReadiness, EndpointStatus},
+			wantProtected: map[string]bool{
+				"/health":          true,
+				"/health/live":     false,
+				"/health/ready":    true,
+				"/health/ready/db": true,
+				"/health/startup":  false,
+				"/status":          true,
+				"/metrics":         false,
+				"/readyz":          true,
+			},
+		},
+	}
+
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+	bs.RegisterChecker(NewChecker("db", func(ctx context.Context) error { return nil }))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHTTPHandler(bs,
+				WithAuthentication(AnyOf(BearerToken("secret"), BasicAuth(map[string]string{"sre": "pw"})), tt.endpoints...),
+				WithRoutes(RouteOptions{Aliases: map[string]string{"/readyz": defaultReadinessPath}}),
+			)
+
+			for path, protected := range tt.wantProtected {
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+				if !protected {
+					assert.Equal(t, http.StatusOK, rec.Code, path)
+					continue
+				}
+				assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+				assert.Equal(t, []string{`Bearer realm="health"`, `Basic realm="health"`}, rec.Header().Values("WWW-Authenticate"), path)
+
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set("Authorization", "Bearer secret")
+				rec = httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				assert.Equal(t, http.StatusOK, rec.Code, path)
+			}
+		})
+	}
+}
+
+func TestClient_Authentication(t *testing.T) {
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+	server := httptest.NewServer(NewHTTPHandler(bs, WithAuthentication(AnyOf(
+		BearerToken("secret"),
+		BasicAuth(map[string]string{"sre": "pw"}),
+	))))
+	defer server.Close()
+
+	var tokenCalls int
+	tests := []struct {
+		name           string
+		opts           []ClientOption
+		wantStatusCode int
+		wantErr        string
+	}{
+		{
+			name:           "no credentials",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "bearer token",
+			opts: []ClientOption{WithBearerToken("secret")},
+		},
+		{
+			name:           "wrong bearer token",
+			opts:           []ClientOption{WithBearerToken("guess")},
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "token source",
+			opts: []ClientOption{WithTokenSource(func(ctx context.Context) (string, error) {
+				tokenCalls++
+				return "secret", nil
+			})},
+		},
+		{
+			name: "token source error",
+			opts: []ClientOption{WithTokenSource(func(ctx context.Context) (string, error) {
+				return "", errors.New("token expired")
+			})},
+			wantErr: "getting token: token expired",
+		},
+		{
+			name: "basic auth",
+			opts: []ClientOption{WithBasicAuth("sre", "pw")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(server.URL, tt.opts...)
+			require.NoError(t, err)
+
+			_, err = client.GetStatus(context.Background())
+			switch {
+			case tt.wantErr != "":
+				assert.EqualError(t, err, tt.wantErr)
+			case tt.wantStatusCode != 0:
+				var statusErr *StatusError
+				require.True(t, errors.As(err, &statusErr))
+				assert.Equal(t, tt.wantStatusCode, statusErr.StatusCode)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, 1, tokenCalls)
+}
+
+// newTestCertificate returns a certificate for name signed by parent, or
+// self-signed if parent is nil.
+func newTestCertificate(t *testing.T, name string, parent *tls.Certificate, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	signer, signerKey := template, any(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestClient_WithClientCertificates(t *testing.T) {
+	ca := newTestCertificate(t, "test-ca", nil, x509.ExtKeyUsageAny)
+	clientCert := newTestCertificate(t, "monitor", &ca, x509.ExtKeyUsageClientAuth)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	server := httptest.NewUnstartedServer(NewHTTPHandler(
+		NewBaseServer("test-service", "1.0.0", "test"),
+		WithAuthentication(ClientCertificate("monitor"), EndpointStatus),
+	))
+	server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithHTTPClient(server.Client()), WithClientCertificates(clientCert))
+	require.NoError(t, err)
+	status, err := client.GetStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-service", status.ServiceName)
+
+	client, err = NewClient(server.URL, WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+	_, err = client.GetStatus(context.Background())
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClient_WithClientCertificates_Transport(t *testing.T) {
+	cert := newTestCertificate(t, "monitor", nil, x509.ExtKeyUsageClientAuth)
+
+	_, err := NewClient("https://example.org", WithClientCertificates(cert),
+		WithHTTPClient(&http.Client{Transport: roundTripperFunc(nil)}))
+	assert.ErrorContains(t, err, "client certificates require an *http.Transport")
+}
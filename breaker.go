@@ -0,0 +1,190 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by clients whose circuit breaker is open,
+// without contacting the server.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails every request with ErrCircuitOpen until the cool-down
+	// has passed.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single trial request through, which closes the
+	// circuit if it succeeds and opens it again if it fails.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultCoolDown         = 30 * time.Second
+)
+
+// CircuitBreaker stops a client from calling a target that keeps failing.
+// It opens after a number of consecutive failures, so that calls fail fast
+// instead of waiting for the client timeout, and lets a trial request through
+// once the cool-down has passed. Transport errors, timeouts and 5xx status
+// codes an endpoint does not define count as failures.
+type CircuitBreaker struct {
+	failureThreshold int
+	coolDown         time.Duration
+	onStateChange    func(from, to CircuitState)
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// WithFailureThreshold sets the number of consecutive failures that open the
+// circuit. It defaults to 5.
+func WithFailureThreshold(failures int) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.failureThreshold = failures
+	}
+}
+
+// WithCoolDown sets how long the circuit stays open before a trial request is
+// let through. It defaults to 30 seconds.
+func WithCoolDown(coolDown time.Duration) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.coolDown = coolDown
+	}
+}
+
+// WithStateChangeHook calls fn on every state transition, e.g. to export the
+// state as a metric. fn must not block.
+func WithStateChangeHook(fn func(from, to CircuitState)) CircuitBreakerOption {
+	return func(b *CircuitBreaker) {
+		b.onStateChange = fn
+	}
+}
+
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		failureThreshold: defaultFailureThreshold,
+		coolDown:         defaultCoolDown,
+		now:              time.Now,
+		state:            CircuitClosed,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// WithCircuitBreaker guards every request of the client with breaker. Use
+// one breaker per target.
+func WithCircuitBreaker(breaker *CircuitBreaker) ClientOption {
+	return func(c *client) {
+		c.breaker = breaker
+	}
+}
+
+// State returns the current state. An open circuit whose cool-down has passed
+// is reported as half-open.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.coolDown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow returns ErrCircuitOpen if a request may not be sent now.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.coolDown {
+			return ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+		b.trial = true
+		return nil
+	case CircuitHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of a request let through by
+// allow.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.trial = false
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up; this says nothing about the target.
+	case isBreakerFailure(err):
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+			b.openedAt = b.now()
+			b.setState(CircuitOpen)
+		}
+	case answered(err):
+		b.failures = 0
+		b.setState(CircuitClosed)
+	}
+}
+
+// answered reports whether a request that failed with err, if at all, got a
+// response from the target.
+func answered(err error) bool {
+	var statusErr *StatusError
+	var decodeErr *DecodeError
+	return err == nil || errors.As(err, &statusErr) || errors.As(err, &decodeErr)
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.onStateChange != nil {
+		b.onStateChange(from, state)
+	}
+}
+
+func isBreakerFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var transportErr *TransportError
+	var timeoutErr *TimeoutError
+	return errors.As(err, &transportErr) || errors.As(err, &timeoutErr)
+}
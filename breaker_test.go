@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for CircuitBreaker.now.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(opts ...CircuitBreakerOption) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Now()}
+	b := NewCircuitBreaker(opts...)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var transitions []string
+	b, clock := newTestBreaker(
+		WithFailureThreshold(2),
+		WithCoolDown(time.Minute),
+		WithStateChangeHook(func(from, to CircuitState) {
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		}),
+	)
+	failure := &TransportError{Err: errors.New("connection refused")}
+
+	require.NoError(t, b.allow())
+	b.record(failure)
+	assert.Equal(t, CircuitClosed, b.State())
+
+	require.NoError(t, b.allow())
+	b.record(nil)
+	require.NoError(t, b.allow())
+	b.record(failure)
+	assert.Equal(t, CircuitClosed, b.State(), "successes reset the failure count")
+
+	require.NoError(t, b.allow())
+	b.record(failure)
+	assert.Equal(t, CircuitOpen, b.State())
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	require.NoError(t, b.allow(), "trial request")
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen, "only one trial request at a time")
+	b.record(failure)
+	assert.Equal(t, CircuitOpen, b.State(), "failed trial reopens the circuit")
+	assert.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	clock.Advance(time.Minute)
+	require.NoError(t, b.allow())
+	b.record(nil)
+	assert.Equal(t, CircuitClosed, b.State())
+	require.NoError(t, b.allow())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half_open",
+		"half_open->open",
+		"open->half_open",
+		"half_open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreaker_Failures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want CircuitState
+	}{
+		{name: "transport error", err: &TransportError{}, want: CircuitOpen},
+		{name: "timeout", err: &TimeoutError{}, want: CircuitOpen},
+		{name: "server error", err: &StatusError{StatusCode: http.StatusBadGateway}, want: CircuitOpen},
+		{name: "client error", err: &StatusError{StatusCode: http.StatusNotFound}, want: CircuitClosed},
+		{name: "decode error", err: &DecodeError{}, want: CircuitClosed},
+		{name: "cancelled", err: &TransportError{Err: context.Canceled}, want: CircuitClosed},
+		{name: "unclassified error", err: errors.New("building request: invalid URL"), want: CircuitClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := newTestBreaker(WithFailureThreshold(1))
+			require.NoError(t, b.allow())
+			b.record(tt.err)
+			assert.Equal(t, tt.want, b.State())
+		})
+	}
+}
+
+func TestClient_WithCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status":"unhealthy","timestamp":"2024-01-06T15:04:05Z"}`))
+	}))
+	defer server.Close()
+
+	breaker, clock := newTestBreaker(WithFailureThreshold(3), WithCoolDown(time.Minute))
+	client, err := NewClient(server.URL,
+		WithCircuitBreaker(breaker),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetHealth(ctx)
+	assert.ErrorIs(t, err, ErrCircuitOpen, "retries stop once the circuit opens")
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	_, err = client.GetMetrics(ctx)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), requests.Load(), "open circuit fails without a request")
+
+	healthy.Store(true)
+	clock.Advance(time.Minute)
+	resp, err := client.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusUnhealthy, resp.Status)
+	assert.Equal(t, CircuitClosed, breaker.State(), "a failing probe is still an answer")
+}
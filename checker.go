@@ -0,0 +1,479 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultCheckTimeout = 5 * time.Second
+
+// Checker is a single named dependency check. Check should honour ctx and
+// report the state of the dependency; Duration and LastSuccess are filled in
+// by the server. An empty Status is treated as healthy unless Error is set.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) CheckResult
+}
+
+type CheckOption func(*registeredCheck)
+
+// WithCheckTimeout bounds how long a single run of the check may take.
+func WithCheckTimeout(timeout time.Duration) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.timeout = timeout
+	}
+}
+
+// WithCriticality sets how a failure of the check affects readiness. Checks
+// are critical by default.
+func WithCriticality(criticality Criticality) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.criticality = criticality
+	}
+}
+
+// WithCheckInterval runs the check in the background every interval once
+// BaseServer.StartBackgroundChecks has been called. Probes are then served
+// from the most recent cached result instead of running the check inline.
+func WithCheckInterval(interval time.Duration) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.interval = interval
+	}
+}
+
+// WithStaleAfter sets the age after which a cached background result is
+// reported as failed. It defaults to three check intervals.
+func WithStaleAfter(staleAfter time.Duration) CheckOption {
+	return func(rc *registeredCheck) {
+		rc.staleAfter = staleAfter
+	}
+}
+
+type checkerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewChecker adapts a plain function into a Checker. A nil error reports the
+// check as healthy, any other error as unhealthy.
+func NewChecker(name string, fn func(ctx context.Context) error) Checker {
+	return &checkerFunc{name: name, fn: fn}
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) CheckResult {
+	if err := c.fn(ctx); err != nil {
+		return CheckResult{Status: HealthStatusUnhealthy, Error: err.Error()}
+	}
+	return CheckResult{Status: HealthStatusHealthy}
+}
+
+type registeredCheck struct {
+	checker     Checker
+	timeout     time.Duration
+	criticality Criticality
+	interval    time.Duration
+	staleAfter  time.Duration
+
+	metrics *checkMetrics
+
+	mu                  sync.Mutex
+	lastSuccess         *time.Time
+	consecutiveFailures int
+	running             bool
+	cancel              context.CancelFunc
+	generation          uint64
+	cached              *CheckResult
+}
+
+func (rc *registeredCheck) run(ctx context.Context) CheckResult {
+	start := time.Now()
+	result := rc.execute(ctx)
+	result.Duration = time.Since(start)
+	result.Criticality = rc.criticality
+
+	if result.Status == "" {
+		result.Status = HealthStatusHealthy
+		if result.Error != "" {
+			result.Status = HealthStatusUnhealthy
+		}
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if result.Status == HealthStatusHealthy {
+		rc.lastSuccess = &start
+	}
+	if result.Status == HealthStatusUnhealthy {
+		rc.consecutiveFailures++
+	} else {
+		rc.consecutiveFailures = 0
+	}
+	result.LastSuccess = rc.lastSuccess
+	result.CheckedAt = &start
+
+	rc.metrics.observe(rc.checker.Name(), result, rc.consecutiveFailures)
+
+	return result
+}
+
+func (rc *registeredCheck) startBackground(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+
+	rc.mu.Lock()
+	rc.running = true
+	rc.cancel = cancel
+	rc.generation++
+	generation := rc.generation
+	rc.mu.Unlock()
+
+	go rc.loop(ctx, generation)
+}
+
+func (rc *registeredCheck) stopBackground() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.cancel != nil {
+		rc.cancel()
+	}
+}
+
+// loop runs the check every interval until ctx is cancelled. generation
+// identifies the startBackground call that launched it, so that a loop
+// exiting after a restart leaves the state of its successor alone.
+func (rc *registeredCheck) loop(ctx context.Context, generation uint64) {
+	defer func() {
+		rc.mu.Lock()
+		if rc.generation == generation {
+			rc.running = false
+			rc.cancel = nil
+		}
+		rc.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(rc.interval)
+	defer ticker.Stop()
+
+	for {
+		result := rc.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		rc.mu.Lock()
+		rc.cached = &result
+		rc.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cachedResult returns the latest background result. The second return value
+// is false when the check is not running in the background and has to be
+// executed inline.
+func (rc *registeredCheck) cachedResult() (CheckResult, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if !rc.running {
+		return CheckResult{}, false
+	}
+
+	if rc.cached == nil {
+		return CheckResult{
+			Status:      HealthStatusUnhealthy,
+			Criticality: rc.criticality,
+			Error:       "check has not completed yet",
+		}, true
+	}
+
+	result := *rc.cached
+	staleAfter := rc.staleAfter
+	if staleAfter <= 0 {
+		staleAfter = 3 * rc.interval
+	}
+	if age := time.Since(*result.CheckedAt); age > staleAfter {
+		result.Status = HealthStatusUnhealthy
+		result.Error = fmt.Sprintf("result is stale: last checked %s ago", age.Round(time.Millisecond))
+	}
+
+	return result, true
+}
+
+func (rc *registeredCheck) execute(ctx context.Context) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, rc.timeout)
+	defer cancel()
+
+	resc := make(chan CheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resc <- CheckResult{
+					Status: HealthStatusUnhealthy,
+					Error:  fmt.Sprintf("check panicked: %v", r),
+				}
+			}
+		}()
+		resc <- rc.checker.Check(ctx)
+	}()
+
+	select {
+	case result := <-resc:
+		return result
+	case <-ctx.Done():
+		err := ctx.Err().Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Sprintf("check timed out after %s", rc.timeout)
+		}
+		return CheckResult{Status: HealthStatusUnhealthy, Error: err}
+	}
+}
+
+type checkRegistry struct {
+	mu     sync.RWMutex
+	checks []*registeredCheck
+	bgCtx  context.Context
+}
+
+func (r *checkRegistry) register(checker Checker, metrics *checkMetrics, opts ...CheckOption) {
+	rc := &registeredCheck{
+		checker:     checker,
+		timeout:     defaultCheckTimeout,
+		criticality: CriticalityCritical,
+		metrics:     metrics,
+	}
+	for _, opt := range opts {
+		opt(rc)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bgCtx != nil && r.bgCtx.Err() == nil && rc.interval > 0 {
+		rc.startBackground(r.bgCtx)
+	}
+
+	for i, existing := range r.checks {
+		if existing.checker.Name() == checker.Name() {
+			existing.stopBackground()
+			r.checks[i] = rc
+			return
+		}
+	}
+	r.checks = append(r.checks, rc)
+}
+
+// start launches the background loops of all checks with an interval. Checks
+// registered afterwards are started as they are added, until ctx is
+// cancelled.
+func (r *checkRegistry) start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.bgCtx != nil && r.bgCtx.Err() == nil {
+		return
+	}
+	r.bgCtx = ctx
+
+	for _, rc := range r.checks {
+		if rc.interval > 0 {
+			rc.startBackground(ctx)
+		}
+	}
+}
+
+func (r *checkRegistry) snapshot() []*registeredCheck {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	checks := make([]*registeredCheck, len(r.checks))
+	copy(checks, r.checks)
+	return checks
+}
+
+// has reports whether a check called name is registered.
+func (r *checkRegistry) has(name string) bool {
+	for _, rc := range r.snapshot() {
+		if rc.checker.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runAll returns the result of every registered check keyed by check name.
+func (r *checkRegistry) runAll(ctx context.Context) map[string]CheckResult {
+	return r.run(ctx, CheckFilter{})
+}
+
+// run returns the result of every check selected by filter keyed by check
+// name. Checks running in the background are served from their cache; the
+// others are executed concurrently.
+func (r *checkRegistry) run(ctx context.Context, filter CheckFilter) map[string]CheckResult {
+	checks := r.snapshot()
+	results := make(map[string]CheckResult, len(checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, rc := range checks {
+		if !filter.matches(rc.checker.Name()) {
+			continue
+		}
+		wg.Add(1)
+		go func(rc *registeredCheck) {
+			defer wg.Done()
+			result, ok := rc.cachedResult()
+			if !ok {
+				result = rc.run(ctx)
+			}
+
+			mu.Lock()
+			results[rc.checker.Name()] = result
+			mu.Unlock()
+		}(rc)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// checkFuncState tracks the checks returned by BaseServer.CheckFunc across
+// calls, the way registeredCheck does for a Checker, and exports them as
+// readiness check metrics.
+type checkFuncState struct {
+	mu                  sync.Mutex
+	metrics             *checkMetrics
+	duration            *Histogram
+	lastSuccess         map[string]time.Time
+	consecutiveFailures map[string]int
+}
+
+// run calls fn and converts its values into check results that carry the
+// time of their last success. Metrics are exported into reg, if not nil; the
+// call as a whole is timed because fn cannot report the duration of its
+// checks.
+func (st *checkFuncState) run(ctx context.Context, reg *Registry, fn func(ctx context.Context) map[string]string) map[string]CheckResult {
+	start := time.Now()
+	values := fn(ctx)
+	elapsed := time.Since(start)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.lastSuccess == nil {
+		st.lastSuccess = make(map[string]time.Time)
+		st.consecutiveFailures = make(map[string]int)
+	}
+	if st.metrics == nil && reg != nil {
+		st.metrics = newCheckMetrics(reg, "readiness")
+		st.duration = reg.NewHistogram(MetricOpts{
+			Name: "health_check_func_duration_seconds",
+			Help: "Time taken to call CheckFunc",
+		})
+	}
+	if st.duration != nil {
+		st.duration.Observe(elapsed.Seconds())
+	}
+
+	results := make(map[string]CheckResult, len(values))
+	for name, value := range values {
+		result := legacyCheckResult(value)
+		if result.Status == HealthStatusHealthy {
+			st.lastSuccess[name] = start
+		}
+		if result.Status == HealthStatusUnhealthy {
+			st.consecutiveFailures[name]++
+		} else {
+			st.consecutiveFailures[name] = 0
+		}
+		if t, ok := st.lastSuccess[name]; ok {
+			result.LastSuccess = &t
+		}
+		results[name] = result
+
+		st.metrics.observeState(name, result, st.consecutiveFailures[name])
+	}
+
+	return results
+}
+
+var checkStatuses = []HealthStatus{HealthStatusHealthy, HealthStatusDegraded, HealthStatusUnhealthy}
+
+// checkMetrics exports the outcome of every check run into a Registry. A nil
+// *checkMetrics discards observations.
+type checkMetrics struct {
+	probe       string
+	status      *Gauge
+	duration    *Histogram
+	failures    *Gauge
+	lastSuccess *Gauge
+}
+
+func newCheckMetrics(reg *Registry, probe string) *checkMetrics {
+	if reg == nil {
+		return nil
+	}
+
+	return &checkMetrics{
+		probe: probe,
+		status: reg.NewGauge(MetricOpts{
+			Name:   "health_check_status",
+			Help:   "Current status of a health check, 1 for the active status",
+			Labels: []string{"check", "probe", "status"},
+		}),
+		duration: reg.NewHistogram(MetricOpts{
+			Name:   "health_check_duration_seconds",
+			Help:   "Time taken to run a health check",
+			Labels: []string{"check", "probe"},
+		}),
+		failures: reg.NewGauge(MetricOpts{
+			Name:   "health_check_consecutive_failures",
+			Help:   "Number of consecutive unhealthy runs of a health check",
+			Labels: []string{"check", "probe"},
+		}),
+		lastSuccess: reg.NewGauge(MetricOpts{
+			Name:   "health_check_last_success_timestamp_seconds",
+			Help:   "Unix time of the last healthy run of a health check",
+			Labels: []string{"check", "probe"},
+		}),
+	}
+}
+
+func (m *checkMetrics) observe(name string, result CheckResult, consecutiveFailures int) {
+	if m == nil {
+		return
+	}
+
+	m.observeState(name, result, consecutiveFailures)
+	m.duration.Observe(result.Duration.Seconds(), name, m.probe)
+}
+
+// observeState exports the outcome of a check run without its duration.
+func (m *checkMetrics) observeState(name string, result CheckResult, consecutiveFailures int) {
+	if m == nil {
+		return
+	}
+
+	for _, status := range checkStatuses {
+		value := 0.0
+		if result.Status == status {
+			value = 1
+		}
+		m.status.Set(value, name, m.probe, string(status))
+	}
+	m.failures.Set(float64(consecutiveFailures), name, m.probe)
+	if result.LastSuccess != nil {
+		m.lastSuccess.Set(float64(result.LastSuccess.UnixNano())/1e9, name, m.probe)
+	}
+}
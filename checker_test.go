@@ -0,0 +1,473 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseServer_RegisterChecker(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   []Checker
+		opts       []CheckOption
+		checkFunc  func(ctx context.Context) map[string]string
+		wantReady  bool
+		wantChecks map[string]string
+	}{
+		{
+			name: "all checkers pass",
+			checkers: []Checker{
+				NewChecker("database", func(ctx context.Context) error { return nil }),
+				NewChecker("cache", func(ctx context.Context) error { return nil }),
+			},
+			wantReady: true,
+			wantChecks: map[string]string{
+				"database": "healthy",
+				"cache":    "healthy",
+			},
+		},
+		{
+			name: "checker fails",
+			checkers: []Checker{
+				NewChecker("database", func(ctx context.Context) error { return nil }),
+				NewChecker("cache", func(ctx context.Context) error { return errors.New("connection refused") }),
+			},
+			wantReady: false,
+			wantChecks: map[string]string{
+				"database": "healthy",
+				"cache":    "connection refused",
+			},
+		},
+		{
+			name: "checker times out",
+			checkers: []Checker{
+				NewChecker("slow", func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+			},
+			opts:      []CheckOption{WithCheckTimeout(10 * time.Millisecond)},
+			wantReady: false,
+			wantChecks: map[string]string{
+				"slow": "check timed out after 10ms",
+			},
+		},
+		{
+			name: "checker ignores context",
+			checkers: []Checker{
+				NewChecker("stuck", func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}),
+			},
+			opts:      []CheckOption{WithCheckTimeout(10 * time.Millisecond)},
+			wantReady: false,
+			wantChecks: map[string]string{
+				"stuck": "check timed out after 10ms",
+			},
+		},
+		{
+			name: "checker panics",
+			checkers: []Checker{
+				NewChecker("broken", func(ctx context.Context) error { panic("boom") }),
+			},
+			wantReady: false,
+			wantChecks: map[string]string{
+				"broken": "check panicked: boom",
+			},
+		},
+		{
+			name: "checkers merged with CheckFunc",
+			checkers: []Checker{
+				NewChecker("cache", func(ctx context.Context) error { return nil }),
+			},
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{"database": "connected"}
+			},
+			wantReady: true,
+			wantChecks: map[string]string{
+				"database": "connected",
+				"cache":    "healthy",
+			},
+		},
+		{
+			name: "same name replaces previous checker",
+			checkers: []Checker{
+				NewChecker("database", func(ctx context.Context) error { return errors.New("old") }),
+				NewChecker("database", func(ctx context.Context) error { return nil }),
+			},
+			wantReady: true,
+			wantChecks: map[string]string{
+				"database": "healthy",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			server.CheckFunc = tt.checkFunc
+			for _, c := range tt.checkers {
+				server.RegisterChecker(c, tt.opts...)
+			}
+
+			resp, err := server.GetReadiness(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReady, resp.Ready)
+			assert.Equal(t, tt.wantChecks, legacyChecks(resp.Checks))
+		})
+	}
+}
+
+type staticChecker struct {
+	name   string
+	result CheckResult
+}
+
+func (c *staticChecker) Name() string {
+	return c.name
+}
+
+func (c *staticChecker) Check(ctx context.Context) CheckResult {
+	return c.result
+}
+
+func TestBaseServer_RegisterChecker_StructuredResults(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(&staticChecker{
+		name:   "pool",
+		result: CheckResult{Message: "12 of 20 connections in use", ObservedValue: 12},
+	})
+	server.RegisterChecker(&staticChecker{
+		name:   "disk",
+		result: CheckResult{Error: "disk full"},
+	})
+
+	resp, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+
+	pool := resp.Checks["pool"]
+	assert.Equal(t, HealthStatusHealthy, pool.Status)
+	assert.Equal(t, "12 of 20 connections in use", pool.Message)
+	assert.Equal(t, 12, pool.ObservedValue)
+	require.NotNil(t, pool.LastSuccess)
+	assert.WithinDuration(t, time.Now(), *pool.LastSuccess, time.Second)
+
+	disk := resp.Checks["disk"]
+	assert.Equal(t, HealthStatusUnhealthy, disk.Status)
+	assert.Equal(t, "disk full", disk.Error)
+	assert.Nil(t, disk.LastSuccess)
+}
+
+func TestBaseServer_RegisterChecker_LastSuccessSurvivesFailure(t *testing.T) {
+	fail := false
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		if fail {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+
+	first, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, first.Checks["database"].LastSuccess)
+
+	fail = true
+	second, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusUnhealthy, second.Checks["database"].Status)
+	assert.Equal(t, first.Checks["database"].LastSuccess, second.Checks["database"].LastSuccess)
+}
+
+func TestBaseServer_RegisterChecker_RunsConcurrently(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	for _, name := range []string{"a", "b", "c", "d"} {
+		server.RegisterChecker(NewChecker(name, func(ctx context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}))
+	}
+
+	start := time.Now()
+	resp, err := server.GetReadiness(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, resp.Ready)
+	assert.Len(t, resp.Checks, 4)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestBaseServer_RegisterChecker_Criticality(t *testing.T) {
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	passing := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name        string
+		criticality Criticality
+		check       func(ctx context.Context) error
+		wantReady   bool
+		wantStatus  HealthStatus
+		wantFailed  []string
+	}{
+		{
+			name:        "critical failure",
+			criticality: CriticalityCritical,
+			check:       failing,
+			wantReady:   false,
+			wantStatus:  HealthStatusUnhealthy,
+			wantFailed:  []string{"dependency"},
+		},
+		{
+			name:        "non-critical failure degrades",
+			criticality: CriticalityNonCritical,
+			check:       failing,
+			wantReady:   true,
+			wantStatus:  HealthStatusDegraded,
+		},
+		{
+			name:        "informational failure is ignored",
+			criticality: CriticalityInformational,
+			check:       failing,
+			wantReady:   true,
+			wantStatus:  HealthStatusHealthy,
+		},
+		{
+			name:        "non-critical success",
+			criticality: CriticalityNonCritical,
+			check:       passing,
+			wantReady:   true,
+			wantStatus:  HealthStatusHealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			server.RegisterChecker(NewChecker("database", passing))
+			server.RegisterChecker(NewChecker("dependency", tt.check), WithCriticality(tt.criticality))
+
+			resp, err := server.GetReadiness(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantReady, resp.Ready)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Equal(t, tt.wantFailed, resp.FailedChecks)
+			assert.Equal(t, tt.criticality, resp.Checks["dependency"].Criticality)
+			assert.Equal(t, CriticalityCritical, resp.Checks["database"].Criticality)
+		})
+	}
+}
+
+func TestBaseServer_StartBackgroundChecks(t *testing.T) {
+	var calls atomic.Int32
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), WithCheckInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server.StartBackgroundChecks(ctx)
+
+	require.Eventually(t, func() bool {
+		resp, err := server.GetReadiness(context.Background())
+		return err == nil && resp.Ready
+	}, time.Second, 5*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		resp, err := server.GetReadiness(context.Background())
+		require.NoError(t, err)
+		assert.True(t, resp.Ready)
+		require.NotNil(t, resp.Checks["database"].CheckedAt)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestBaseServer_StartBackgroundChecks_Pending(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		<-release
+		return nil
+	}), WithCheckInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server.StartBackgroundChecks(ctx)
+
+	resp, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, "check has not completed yet", resp.Checks["database"].Error)
+}
+
+func TestBaseServer_StartBackgroundChecks_Stale(t *testing.T) {
+	var calls atomic.Int32
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		if calls.Add(1) > 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}), WithCheckInterval(10*time.Millisecond), WithStaleAfter(30*time.Millisecond), WithCheckTimeout(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server.StartBackgroundChecks(ctx)
+
+	require.Eventually(t, func() bool {
+		resp, err := server.GetReadiness(context.Background())
+		return err == nil && resp.Ready
+	}, time.Second, time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		resp, err := server.GetReadiness(context.Background())
+		return err == nil && !resp.Ready
+	}, time.Second, 5*time.Millisecond)
+
+	resp, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, resp.Checks["database"].Error, "result is stale")
+	assert.NotNil(t, resp.Checks["database"].LastSuccess)
+}
+
+func TestBaseServer_StartBackgroundChecks_Stopped(t *testing.T) {
+	var calls atomic.Int32
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), WithCheckInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server.StartBackgroundChecks(ctx)
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+
+	require.Eventually(t, func() bool {
+		before := calls.Load()
+		_, err := server.GetReadiness(context.Background())
+		return err == nil && calls.Load() == before+1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBaseServer_CheckMetrics(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error { return nil }))
+	server.RegisterChecker(NewChecker("cache", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+	server.NewHeartbeat("worker", time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err := server.GetReadiness(context.Background())
+		require.NoError(t, err)
+	}
+	_, err := server.GetLiveness(context.Background())
+	require.NoError(t, err)
+
+	metrics, err := server.GetMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, `health_check_status{check="cache",probe="readiness",status="unhealthy"} 1`)
+	assert.Contains(t, metrics, `health_check_status{check="cache",probe="readiness",status="healthy"} 0`)
+	assert.Contains(t, metrics, `health_check_status{check="database",probe="readiness",status="healthy"} 1`)
+	assert.Contains(t, metrics, `health_check_status{check="worker",probe="liveness",status="healthy"} 1`)
+	assert.Contains(t, metrics, `health_check_consecutive_failures{check="cache",probe="readiness"} 2`)
+	assert.Contains(t, metrics, `health_check_consecutive_failures{check="database",probe="readiness"} 0`)
+	assert.Contains(t, metrics, `health_check_duration_seconds_count{check="database",probe="readiness"} 2`)
+	assert.Contains(t, metrics, `health_check_last_success_timestamp_seconds{check="database",probe="readiness"}`)
+	assert.NotContains(t, metrics, `health_check_last_success_timestamp_seconds{check="cache"`)
+}
+
+func TestBaseServer_CheckFuncMetrics(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.CheckFunc = func(ctx context.Context) map[string]string {
+		return map[string]string{"database": "ok", "cache": "connection refused", "queue": "degraded"}
+	}
+
+	var resp *ReadinessResponse
+	for i := 0; i < 2; i++ {
+		var err error
+		resp, err = server.GetReadiness(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.NotNil(t, resp.Checks["database"].LastSuccess)
+	assert.WithinDuration(t, time.Now(), *resp.Checks["database"].LastSuccess, time.Second)
+	assert.Nil(t, resp.Checks["cache"].LastSuccess)
+
+	metrics, err := server.GetMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, `health_check_status{check="database",probe="readiness",status="healthy"} 1`)
+	assert.Contains(t, metrics, `health_check_status{check="cache",probe="readiness",status="unhealthy"} 1`)
+	assert.Contains(t, metrics, `health_check_status{check="queue",probe="readiness",status="degraded"} 1`)
+	assert.Contains(t, metrics, `health_check_consecutive_failures{check="cache",probe="readiness"} 2`)
+	assert.Contains(t, metrics, `health_check_consecutive_failures{check="database",probe="readiness"} 0`)
+	assert.Contains(t, metrics, `health_check_last_success_timestamp_seconds{check="database",probe="readiness"}`)
+	assert.NotContains(t, metrics, `health_check_last_success_timestamp_seconds{check="cache"`)
+	assert.Contains(t, metrics, "health_check_func_duration_seconds_count 2")
+	assert.NotContains(t, metrics, `health_check_duration_seconds_count{check="database"`)
+}
+
+func TestBaseServer_StartBackgroundChecks_Restarted(t *testing.T) {
+	var calls atomic.Int32
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), WithCheckInterval(time.Hour))
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	server.StartBackgroundChecks(first)
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+	cancelFirst()
+	server.StartBackgroundChecks(second)
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		resp, err := server.GetReadiness(context.Background())
+		require.NoError(t, err)
+		assert.True(t, resp.Ready)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestBaseServer_StartBackgroundChecks_RegisteredAfterStop(t *testing.T) {
+	var calls atomic.Int32
+	server := NewBaseServer("test-service", "1.0.0", "test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server.StartBackgroundChecks(ctx)
+	cancel()
+
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), WithCheckInterval(time.Hour))
+
+	for i := 1; i <= 3; i++ {
+		resp, err := server.GetReadiness(context.Background())
+		require.NoError(t, err)
+		assert.True(t, resp.Ready)
+		assert.Equal(t, int32(i), calls.Load())
+	}
+}
@@ -2,20 +2,31 @@ package health
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
+// Client queries the health endpoints of a service. Probe and status
+// responses carry the HTTP status code they were served with in StatusCode,
+// so a 503 with a body can be told apart from a 200. Failures are reported as
+// *StatusError, *DecodeError, *TransportError or *TimeoutError, and as
+// ErrCircuitOpen when a circuit breaker rejects the request.
 type Client interface {
 	GetHealth(ctx context.Context) (*HealthResponse, error)
 	GetLiveness(ctx context.Context) (*LivenessResponse, error)
+	GetStartup(ctx context.Context) (*StartupResponse, error)
 	GetReadiness(ctx context.Context) (*ReadinessResponse, error)
+	// GetCheck returns the result of the single readiness check called name.
+	GetCheck(ctx context.Context, name string) (*CheckResult, error)
 	GetStatus(ctx context.Context) (*StatusResponse, error)
 	GetMetrics(ctx context.Context) (string, error)
+	GetMetricFamilies(ctx context.Context) (MetricFamilies, error)
 }
 
 type ClientOption func(*client)
@@ -23,6 +34,13 @@ type ClientOption func(*client)
 type client struct {
 	baseURL    string
 	httpClient *http.Client
+	routes     RouteOptions
+	retry      RetryPolicy
+	breaker    *CircuitBreaker
+
+	tokenSource  func(ctx context.Context) (string, error)
+	basicAuth    *[2]string
+	certificates []tls.Certificate
 }
 
 func NewClient(baseURL string, opts ...ClientOption) (Client, error) {
@@ -42,6 +60,10 @@ func NewClient(baseURL string, opts ...ClientOption) (Client, error) {
 		opt(c)
 	}
 
+	if err := c.configureCertificates(); err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -57,126 +79,256 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
-func (c *client) doRequest(ctx context.Context, method, path string) (*http.Response, error) {
-	reqURL := c.baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+// jsonAccept prefers the package's own JSON responses but also accepts the
+// application/health+json format served by third-party services.
+const jsonAccept = "application/json, application/health+json;q=0.9"
+
+// doRequest requests path relative to the base URL, retrying according to
+// the client's RetryPolicy. Callers resolve the path through c.routes.
+// Responses with status codes other than expected are returned as a
+// *StatusError; failures to get a response as a *TransportError or a
+// *TimeoutError, or ErrCircuitOpen if the circuit breaker rejects the request.
+func (c *client) doRequest(ctx context.Context, method, path, accept string, expected ...int) (*http.Response, error) {
+	attempts := 1
+	if isIdempotent(method) {
+		attempts = c.retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, method, path, accept, expected)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !c.retry.retryable(err) {
+			return resp, err
+		}
+
+		delay, ok := c.retry.delay(attempt, err)
+		if !ok {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a single request through the circuit breaker, if any.
+func (c *client) attempt(ctx context.Context, method, path, accept string, expected []int) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.send(ctx, method, path, accept, expected)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	resp, err := c.send(ctx, method, path, accept, expected)
+	c.breaker.record(err)
+	return resp, err
+}
+
+func (c *client) send(ctx context.Context, method, path, accept string, expected []int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept", accept)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		return nil, requestError(err)
+	}
+
+	if err := checkStatus(resp, expected...); err != nil {
+		resp.Body.Close()
+		return nil, err
 	}
 
 	return resp, nil
 }
 
+// checkStatus returns a *StatusError carrying the response body and headers
+// unless resp has one of the expected status codes.
+func checkStatus(resp *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return &StatusError{StatusCode: resp.StatusCode, Body: string(body), Header: resp.Header}
+}
+
+func decodeJSON(resp *http.Response, v interface{}) error {
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return &DecodeError{StatusCode: resp.StatusCode, Err: err}
+	}
+	return nil
+}
+
 func (c *client) GetHealth(ctx context.Context) (*HealthResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/health")
+	resp, err := c.doRequest(ctx, http.MethodGet, c.routes.path(defaultHealthPath), jsonAccept, http.StatusOK, http.StatusServiceUnavailable)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-	}
-
 	var healthResp HealthResponse
-	if err := json.NewDecoder(resp.Body).Decode(&healthResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if isHealthJSON(resp) {
+		doc, err := decodeHealthJSON(resp)
+		if err != nil {
+			return nil, err
+		}
+		healthResp = *doc.healthResponse()
+	} else if err := decodeJSON(resp, &healthResp); err != nil {
+		return nil, err
 	}
 
+	healthResp.StatusCode = resp.StatusCode
 	return &healthResp, nil
 }
 
 func (c *client) GetLiveness(ctx context.Context) (*LivenessResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/health/live")
+	resp, err := c.doRequest(ctx, http.MethodGet, c.routes.path(defaultLivenessPath), jsonAccept, http.StatusOK, http.StatusServiceUnavailable)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-	}
-
 	var livenessResp LivenessResponse
-	if err := json.NewDecoder(resp.Body).Decode(&livenessResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if isHealthJSON(resp) {
+		doc, err := decodeHealthJSON(resp)
+		if err != nil {
+			return nil, err
+		}
+		livenessResp = *doc.livenessResponse()
+	} else if err := decodeJSON(resp, &livenessResp); err != nil {
+		return nil, err
 	}
 
+	livenessResp.StatusCode = resp.StatusCode
 	return &livenessResp, nil
 }
 
-func (c *client) GetReadiness(ctx context.Context) (*ReadinessResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/health/ready")
+func (c *client) GetStartup(ctx context.Context) (*StartupResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, c.routes.path(defaultStartupPath), jsonAccept, http.StatusOK, http.StatusServiceUnavailable)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	var startupResp StartupResponse
+	if isHealthJSON(resp) {
+		doc, err := decodeHealthJSON(resp)
+		if err != nil {
+			return nil, err
+		}
+		startupResp = *doc.startupResponse()
+	} else if err := decodeJSON(resp, &startupResp); err != nil {
+		return nil, err
+	}
+
+	startupResp.StatusCode = resp.StatusCode
+	return &startupResp, nil
+}
+
+func (c *client) GetReadiness(ctx context.Context) (*ReadinessResponse, error) {
+	return c.getReadiness(ctx, c.routes.path(defaultReadinessPath))
+}
+
+// GetCheck queries the per-check readiness endpoint, so that the server only
+// runs the requested check. Unknown checks are reported as a *StatusError
+// with status code 404.
+func (c *client) GetCheck(ctx context.Context, name string) (*CheckResult, error) {
+	readinessResp, err := c.getReadiness(ctx, c.routes.path(defaultReadinessPath)+"/"+url.PathEscape(name))
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := readinessResp.Checks[name]
+	if !ok {
+		return nil, &DecodeError{StatusCode: readinessResp.StatusCode, Err: fmt.Errorf("check %q missing from response", name)}
 	}
 
+	return &result, nil
+}
+
+func (c *client) getReadiness(ctx context.Context, path string) (*ReadinessResponse, error) {
+	resp, err := c.doRequest(ctx, http.MethodGet, path, jsonAccept, http.StatusOK, http.StatusServiceUnavailable)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
 	var readinessResp ReadinessResponse
-	if err := json.NewDecoder(resp.Body).Decode(&readinessResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if isHealthJSON(resp) {
+		doc, err := decodeHealthJSON(resp)
+		if err != nil {
+			return nil, err
+		}
+		readinessResp = *doc.readinessResponse()
+	} else if err := decodeJSON(resp, &readinessResp); err != nil {
+		return nil, err
 	}
 
+	readinessResp.StatusCode = resp.StatusCode
 	return &readinessResp, nil
 }
 
 func (c *client) GetStatus(ctx context.Context) (*StatusResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/status")
+	resp, err := c.doRequest(ctx, http.MethodGet, c.routes.path(defaultStatusPath), jsonAccept, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-	}
-
 	var statusResp StatusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if err := decodeJSON(resp, &statusResp); err != nil {
+		return nil, err
 	}
 
+	statusResp.StatusCode = resp.StatusCode
 	return &statusResp, nil
 }
 
+// metricsAccept prefers OpenMetrics but accepts the Prometheus text format
+// from servers that do not support it.
+const metricsAccept = "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5,*/*;q=0.1"
+
 func (c *client) GetMetrics(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/metrics", nil)
+	resp, err := c.doRequest(ctx, http.MethodGet, c.routes.path(defaultMetricsPath), metricsAccept, http.StatusOK)
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set("Accept", "text/plain")
-
-	resp, err := c.httpClient.Do(req)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("executing request: %w", err)
+		return "", requestError(fmt.Errorf("reading response body: %w", err))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	return string(body), nil
+}
+
+// GetMetricFamilies fetches the metrics endpoint and parses the response,
+// whichever exposition format the server chose.
+func (c *client) GetMetricFamilies(ctx context.Context) (MetricFamilies, error) {
+	metrics, err := c.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	families, err := ParseMetrics(strings.NewReader(metrics))
 	if err != nil {
-		return "", fmt.Errorf("reading response body: %w", err)
+		return nil, &DecodeError{StatusCode: http.StatusOK, Err: fmt.Errorf("parsing metrics: %w", err)}
 	}
 
-	return string(body), nil
+	return families, nil
 }
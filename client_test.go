@@ -3,12 +3,14 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -102,6 +104,19 @@ func TestClient_GetHealth(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:           "degraded response",
+			responseStatus: http.StatusOK,
+			responseBody: HealthResponse{
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+			},
+			want: &HealthResponse{
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+			},
+			wantErr: false,
+		},
 		{
 			name:           "invalid status code",
 			responseStatus: http.StatusBadRequest,
@@ -123,7 +138,7 @@ func TestClient_GetHealth(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				assert.Equal(t, "/health", r.URL.Path)
 				assert.Equal(t, http.MethodGet, r.Method)
-				assert.Equal(t, "application/json", r.Header.Get("Accept"))
+				assert.Equal(t, jsonAccept, r.Header.Get("Accept"))
 
 				w.WriteHeader(tt.responseStatus)
 				if tt.responseBody != nil {
@@ -250,6 +265,89 @@ func TestClient_GetLiveness(t *testing.T) {
 	}
 }
 
+func TestClient_GetStartup(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		responseStatus int
+		responseBody   interface{}
+		want           *StartupResponse
+		wantErr        bool
+		errMsg         string
+	}{
+		{
+			name:           "started response",
+			responseStatus: http.StatusOK,
+			responseBody: StartupResponse{
+				Started:   true,
+				Timestamp: now,
+			},
+			want: &StartupResponse{
+				Started:   true,
+				Timestamp: now,
+			},
+		},
+		{
+			name:           "starting response",
+			responseStatus: http.StatusServiceUnavailable,
+			responseBody: StartupResponse{
+				Started:   false,
+				Timestamp: now,
+				Tasks: map[string]CheckResult{
+					"migrations": {Status: HealthStatusUnhealthy, Message: "pending"},
+				},
+			},
+			want: &StartupResponse{
+				Started:   false,
+				Timestamp: now,
+				Tasks: map[string]CheckResult{
+					"migrations": {Status: HealthStatusUnhealthy, Message: "pending"},
+				},
+			},
+		},
+		{
+			name:           "invalid status code",
+			responseStatus: http.StatusNotFound,
+			responseBody:   "404 page not found",
+			wantErr:        true,
+			errMsg:         "unexpected status code 404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/health/startup", r.URL.Path)
+				assert.Equal(t, http.MethodGet, r.Method)
+
+				w.WriteHeader(tt.responseStatus)
+				switch v := tt.responseBody.(type) {
+				case string:
+					_, _ = w.Write([]byte(v))
+				default:
+					_ = json.NewEncoder(w).Encode(v)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL)
+			require.NoError(t, err)
+
+			got, err := client.GetStartup(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want.Started, got.Started)
+				assert.WithinDuration(t, tt.want.Timestamp, got.Timestamp, time.Second)
+				assert.Equal(t, tt.want.Tasks, got.Tasks)
+			}
+		})
+	}
+}
+
 func TestClient_GetReadiness(t *testing.T) {
 	now := time.Now()
 
@@ -267,17 +365,17 @@ func TestClient_GetReadiness(t *testing.T) {
 			responseBody: ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connected",
-					"cache":    "available",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy, Message: "connected"},
+					"cache":    {Status: HealthStatusHealthy, Message: "available"},
 				},
 			},
 			want: &ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connected",
-					"cache":    "available",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy, Message: "connected"},
+					"cache":    {Status: HealthStatusHealthy, Message: "available"},
 				},
 			},
 			wantErr: false,
@@ -288,17 +386,38 @@ func TestClient_GetReadiness(t *testing.T) {
 			responseBody: ReadinessResponse{
 				Ready:     false,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connection failed",
-					"cache":    "unavailable",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusUnhealthy, Error: "connection failed"},
+					"cache":    {Status: HealthStatusUnhealthy, Error: "unavailable"},
 				},
 			},
 			want: &ReadinessResponse{
 				Ready:     false,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connection failed",
-					"cache":    "unavailable",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusUnhealthy, Error: "connection failed"},
+					"cache":    {Status: HealthStatusUnhealthy, Error: "unavailable"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "degraded response",
+			responseStatus: http.StatusServiceUnavailable,
+			responseBody: ReadinessResponse{
+				Ready:     true,
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+				Checks: map[string]CheckResult{
+					"cache": {Status: HealthStatusDegraded, Message: "high latency"},
+				},
+			},
+			want: &ReadinessResponse{
+				Ready:     true,
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+				Checks: map[string]CheckResult{
+					"cache": {Status: HealthStatusDegraded, Message: "high latency"},
 				},
 			},
 			wantErr: false,
@@ -309,12 +428,12 @@ func TestClient_GetReadiness(t *testing.T) {
 			responseBody: ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks:    map[string]string{},
+				Checks:    map[string]CheckResult{},
 			},
 			want: &ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks:    map[string]string{},
+				Checks:    map[string]CheckResult{},
 			},
 			wantErr: false,
 		},
@@ -359,6 +478,7 @@ func TestClient_GetReadiness(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, tt.want.Ready, got.Ready)
+				assert.Equal(t, tt.want.Status, got.Status)
 				assert.WithinDuration(t, tt.want.Timestamp, got.Timestamp, time.Second)
 				assert.Equal(t, tt.want.Checks, got.Checks)
 			}
@@ -366,6 +486,76 @@ func TestClient_GetReadiness(t *testing.T) {
 	}
 }
 
+func TestClient_GetReadiness_LegacyChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"ready":false,"timestamp":"2024-01-06T15:04:05Z","checks":{"database":"connected","cache":"timeout"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	got, err := client.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, got.Ready)
+	assert.Equal(t, map[string]CheckResult{
+		"database": {Status: HealthStatusHealthy, Message: "connected"},
+		"cache":    {Status: HealthStatusUnhealthy, Error: "timeout"},
+	}, got.Checks)
+}
+
+func TestClient_GetCheck(t *testing.T) {
+	var requested []string
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+	bs.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		requested = append(requested, "database")
+		return nil
+	}))
+	bs.RegisterChecker(NewChecker("cache", func(ctx context.Context) error {
+		requested = append(requested, "cache")
+		return errors.New("connection refused")
+	}))
+
+	server := httptest.NewServer(NewHTTPHandler(bs))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	got, err := client.GetCheck(ctx, "cache")
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusUnhealthy, got.Status)
+	assert.Equal(t, "connection refused", got.Error)
+	assert.Equal(t, []string{"cache"}, requested)
+
+	got, err = client.GetCheck(ctx, "database")
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusHealthy, got.Status)
+
+	_, err = client.GetCheck(ctx, "billing")
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
+
+func TestClient_GetCheck_MissingFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health/ready/billing", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ready":true,"status":"healthy","checks":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	_, err = client.GetCheck(context.Background(), "billing")
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, http.StatusOK, decodeErr.StatusCode)
+	assert.ErrorContains(t, err, `check "billing" missing from response`)
+}
+
 func TestClient_GetStatus(t *testing.T) {
 	now := time.Now()
 	buildTime := now.Add(-24 * time.Hour)
@@ -516,6 +706,14 @@ http_requests_total{method="GET",status="200"} 1234
 `,
 			wantErr: false,
 		},
+		{
+			name:           "openmetrics response",
+			responseStatus: http.StatusOK,
+			responseBody:   "# TYPE jobs counter\njobs_total 3\n# EOF\n",
+			contentType:    "application/openmetrics-text; version=1.0.0; charset=utf-8",
+			want:           "# TYPE jobs counter\njobs_total 3\n# EOF\n",
+			wantErr:        false,
+		},
 		{
 			name:           "empty metrics",
 			responseStatus: http.StatusOK,
@@ -553,7 +751,7 @@ http_requests_total{method="GET",status="200"} 1234
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				assert.Equal(t, "/metrics", r.URL.Path)
 				assert.Equal(t, http.MethodGet, r.Method)
-				assert.Equal(t, "text/plain", r.Header.Get("Accept"))
+				assert.Equal(t, metricsAccept, r.Header.Get("Accept"))
 
 				if tt.contentType != "" {
 					w.Header().Set("Content-Type", tt.contentType)
@@ -578,6 +776,145 @@ http_requests_total{method="GET",status="200"} 1234
 	}
 }
 
+func TestClient_GetMetricFamilies(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseStatus int
+		responseBody   string
+		wantValue      float64
+		wantErr        string
+	}{
+		{
+			name:           "prometheus text",
+			responseStatus: http.StatusOK,
+			responseBody: `# HELP queue_depth Jobs waiting
+# TYPE queue_depth gauge
+queue_depth{queue="emails"} 7
+`,
+			wantValue: 7,
+		},
+		{
+			name:           "openmetrics",
+			responseStatus: http.StatusOK,
+			responseBody:   "# TYPE queue_depth gauge\nqueue_depth{queue=\"emails\"} 9\n# EOF\n",
+			wantValue:      9,
+		},
+		{
+			name:           "invalid exposition",
+			responseStatus: http.StatusOK,
+			responseBody:   "queue_depth{queue=\"emails\" 7\n",
+			wantErr:        "parsing metrics",
+		},
+		{
+			name:           "server error",
+			responseStatus: http.StatusInternalServerError,
+			responseBody:   "boom",
+			wantErr:        "unexpected status code 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.responseStatus)
+				_, _ = w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL)
+			require.NoError(t, err)
+
+			families, err := client.GetMetricFamilies(context.Background())
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			value, ok := families.Sample("queue_depth", map[string]string{"queue": "emails"})
+			require.True(t, ok)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestClient_GetMetricFamilies_FromBaseServer(t *testing.T) {
+	hs := NewBaseServer("test-service", "1.0.0", "test")
+	hs.Metrics.NewHistogram(MetricOpts{
+		Name:    "job_duration_seconds",
+		Buckets: []float64{0.1, 1},
+	}).Observe(0.5)
+
+	r := chi.NewRouter()
+	NewHTTPHandler(hs).RegisterRoutes(r)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	families, err := client.GetMetricFamilies(context.Background())
+	require.NoError(t, err)
+
+	f, ok := families.Family("job_duration_seconds")
+	require.True(t, ok)
+	assert.Equal(t, MetricTypeHistogram, f.Type)
+
+	count, ok := families.Sample("job_duration_seconds_bucket", map[string]string{"le": "1"})
+	require.True(t, ok)
+	assert.Equal(t, 1.0, count)
+}
+
+func TestClient_HealthJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/health+json; charset=utf-8")
+		if r.URL.Path == "/health/ready" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{
+				"status": "fail",
+				"checks": {
+					"postgres:responseTime": [{"status": "fail", "observedValue": 250, "observedUnit": "ms", "output": "timeout"}],
+					"uptime": [{"status": "pass", "observedValue": 1209600.245, "observedUnit": "s"}]
+				}
+			}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "warn"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	health, err := client.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, HealthStatusDegraded, health.Status)
+
+	liveness, err := client.GetLiveness(ctx)
+	require.NoError(t, err)
+	assert.True(t, liveness.Alive)
+
+	startup, err := client.GetStartup(ctx)
+	require.NoError(t, err)
+	assert.True(t, startup.Started)
+
+	readiness, err := client.GetReadiness(ctx)
+	require.NoError(t, err)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, HealthStatusUnhealthy, readiness.Status)
+	assert.Equal(t, []string{"postgres"}, readiness.FailedChecks)
+	assert.Equal(t, CheckResult{
+		Status:        HealthStatusUnhealthy,
+		Error:         "timeout",
+		ObservedValue: 250.0,
+		ObservedUnit:  "ms",
+		Measurement:   "responseTime",
+	}, readiness.Checks["postgres"])
+	assert.Equal(t, HealthStatusHealthy, readiness.Checks["uptime"].Status)
+}
+
 func TestClient_ConcurrentRequests(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(10 * time.Millisecond)
@@ -597,7 +934,7 @@ func TestClient_ConcurrentRequests(t *testing.T) {
 			_ = json.NewEncoder(w).Encode(ReadinessResponse{
 				Ready:     true,
 				Timestamp: time.Now(),
-				Checks:    map[string]string{"test": "ok"},
+				Checks:    map[string]CheckResult{"test": {Status: HealthStatusHealthy}},
 			})
 		case "/status":
 			_ = json.NewEncoder(w).Encode(StatusResponse{
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"time"
+)
+
+type goCollector struct{}
+
+// NewGoCollector returns a collector exporting Go runtime metrics: goroutine
+// and thread counts, garbage collection pauses and heap statistics.
+func NewGoCollector() Collector {
+	return &goCollector{}
+}
+
+func (c *goCollector) Collect() []MetricFamily {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	stats := debug.GCStats{PauseQuantiles: make([]time.Duration, 5)}
+	debug.ReadGCStats(&stats)
+
+	gcSummary := &SummaryValue{
+		Count: uint64(stats.NumGC),
+		Sum:   stats.PauseTotal.Seconds(),
+	}
+	for i, q := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		gcSummary.Quantiles = append(gcSummary.Quantiles, Quantile{
+			Quantile: q,
+			Value:    stats.PauseQuantiles[i].Seconds(),
+		})
+	}
+
+	return []MetricFamily{
+		gaugeFamily("go_goroutines", "Number of goroutines that currently exist", float64(runtime.NumGoroutine())),
+		gaugeFamily("go_threads", "Number of OS threads created", float64(pprof.Lookup("threadcreate").Count())),
+		{
+			Name:    "go_gc_duration_seconds",
+			Help:    "A summary of the pause duration of garbage collection cycles",
+			Type:    MetricTypeSummary,
+			Metrics: []Metric{{Summary: gcSummary}},
+		},
+		{
+			Name: "go_info",
+			Help: "Information about the Go environment",
+			Type: MetricTypeGauge,
+			Metrics: []Metric{{
+				Labels: []LabelPair{{Name: "version", Value: runtime.Version()}},
+				Value:  1,
+			}},
+		},
+		{
+			Name:    "go_memstats_alloc_bytes_total",
+			Help:    "Total number of bytes allocated, even if freed",
+			Type:    MetricTypeCounter,
+			Metrics: []Metric{{Value: float64(ms.TotalAlloc)}},
+		},
+		gaugeFamily("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use", float64(ms.HeapAlloc)),
+		gaugeFamily("go_memstats_heap_idle_bytes", "Number of heap bytes waiting to be used", float64(ms.HeapIdle)),
+		gaugeFamily("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use", float64(ms.HeapInuse)),
+		gaugeFamily("go_memstats_heap_objects", "Number of allocated objects", float64(ms.HeapObjects)),
+		gaugeFamily("go_memstats_heap_released_bytes", "Number of heap bytes released to the OS", float64(ms.HeapReleased)),
+		gaugeFamily("go_memstats_heap_sys_bytes", "Number of heap bytes obtained from the system", float64(ms.HeapSys)),
+		gaugeFamily("go_memstats_next_gc_bytes", "Number of heap bytes when the next garbage collection will take place", float64(ms.NextGC)),
+		gaugeFamily("go_memstats_sys_bytes", "Number of bytes obtained from the system", float64(ms.Sys)),
+	}
+}
+
+type processCollector struct{}
+
+// NewProcessCollector returns a collector exporting process metrics: CPU
+// time, resident and virtual memory, file descriptors and start time. The
+// values are read from /proc and are only available on Linux; on other
+// platforms the collector produces no metrics.
+func NewProcessCollector() Collector {
+	return &processCollector{}
+}
+
+func (c *processCollector) Collect() []MetricFamily {
+	stats, err := readProcessStats()
+	if err != nil {
+		return nil
+	}
+
+	families := []MetricFamily{
+		{
+			Name:    "process_cpu_seconds_total",
+			Help:    "Total user and system CPU time spent in seconds",
+			Type:    MetricTypeCounter,
+			Metrics: []Metric{{Value: stats.cpuSeconds}},
+		},
+		gaugeFamily("process_resident_memory_bytes", "Resident memory size in bytes", stats.residentBytes),
+		gaugeFamily("process_virtual_memory_bytes", "Virtual memory size in bytes", stats.virtualBytes),
+		gaugeFamily("process_start_time_seconds", "Start time of the process since unix epoch in seconds", stats.startTime),
+	}
+	if stats.openFDs >= 0 {
+		families = append(families, gaugeFamily("process_open_fds", "Number of open file descriptors", stats.openFDs))
+	}
+	if stats.maxFDs >= 0 {
+		families = append(families, gaugeFamily("process_max_fds", "Maximum number of open file descriptors", stats.maxFDs))
+	}
+	return families
+}
+
+type processStats struct {
+	cpuSeconds    float64
+	residentBytes float64
+	virtualBytes  float64
+	startTime     float64
+	openFDs       float64
+	maxFDs        float64
+}
+
+func gaugeFamily(name, help string, value float64) MetricFamily {
+	return MetricFamily{
+		Name:    name,
+		Help:    help,
+		Type:    MetricTypeGauge,
+		Metrics: []Metric{{Value: value}},
+	}
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func familiesByName(families []MetricFamily) map[string]MetricFamily {
+	byName := make(map[string]MetricFamily, len(families))
+	for _, f := range families {
+		byName[f.Name] = f
+	}
+	return byName
+}
+
+func TestGoCollector(t *testing.T) {
+	families := familiesByName(NewGoCollector().Collect())
+
+	for _, name := range []string{
+		"go_goroutines",
+		"go_threads",
+		"go_gc_duration_seconds",
+		"go_info",
+		"go_memstats_alloc_bytes_total",
+		"go_memstats_heap_alloc_bytes",
+		"go_memstats_heap_objects",
+	} {
+		assert.Contains(t, families, name)
+	}
+
+	assert.Greater(t, families["go_goroutines"].Metrics[0].Value, float64(0))
+	assert.Equal(t, MetricTypeSummary, families["go_gc_duration_seconds"].Type)
+	require.NotNil(t, families["go_gc_duration_seconds"].Metrics[0].Summary)
+	assert.Len(t, families["go_gc_duration_seconds"].Metrics[0].Summary.Quantiles, 5)
+	assert.Equal(t, runtime.Version(), families["go_info"].Metrics[0].Labels[0].Value)
+}
+
+func TestProcessCollector(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		assert.Empty(t, NewProcessCollector().Collect())
+		return
+	}
+
+	families := familiesByName(NewProcessCollector().Collect())
+
+	require.Contains(t, families, "process_start_time_seconds")
+	start := time.Unix(int64(families["process_start_time_seconds"].Metrics[0].Value), 0)
+	assert.WithinDuration(t, time.Now(), start, time.Hour)
+
+	assert.Greater(t, families["process_resident_memory_bytes"].Metrics[0].Value, float64(0))
+	assert.Greater(t, families["process_virtual_memory_bytes"].Metrics[0].Value, float64(0))
+	assert.GreaterOrEqual(t, families["process_cpu_seconds_total"].Metrics[0].Value, float64(0))
+	assert.Greater(t, families["process_open_fds"].Metrics[0].Value, float64(0))
+
+	f, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer f.Close()
+	after := familiesByName(NewProcessCollector().Collect())
+	assert.Greater(t, after["process_open_fds"].Metrics[0].Value, families["process_open_fds"].Metrics[0].Value)
+}
+
+func TestBaseServer_GetMetrics_MergesCollectors(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.Metrics.Register(NewGoCollector())
+	server.MetricsFunc = func(ctx context.Context) (string, error) {
+		return "# TYPE custom_metric gauge\ncustom_metric 42", nil
+	}
+
+	got, err := server.GetMetrics(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "# TYPE custom_metric gauge\ncustom_metric 42\n# HELP go_"))
+	assert.Contains(t, got, "# TYPE go_gc_duration_seconds summary\n")
+	assert.Contains(t, got, `go_gc_duration_seconds{quantile="0.5"}`)
+	assert.Contains(t, got, "go_gc_duration_seconds_count ")
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// Redactor rewrites the messages and errors of check results before they are
+// served, e.g. to remove credentials.
+type Redactor func(s string) string
+
+// WithDetailAuthorization serves the full responses only to requests
+// accepted by auth, such as AnyOf(BearerToken(token), TrustedNetworks(...)).
+// Other requests get the overall outcome of each endpoint: probes without
+// their checks, and the status endpoint without version, git commit, build
+// time, hostname and dependencies.
+func WithDetailAuthorization(auth Authenticator) HandlerOption {
+	return func(h *HTTPHandler) {
+		h.detailAuth = auth
+	}
+}
+
+// WithRedaction applies redactors, in order, to the messages and errors of
+// every check result served, and to the errors returned by the server.
+func WithRedaction(redactors ...Redactor) HandlerOption {
+	return func(h *HTTPHandler) {
+		h.redactors = append(h.redactors, redactors...)
+	}
+}
+
+// RedactPattern replaces every match of re with replacement, which may refer
+// to submatches as in regexp.Regexp.ReplaceAllString.
+func RedactPattern(re *regexp.Regexp, replacement string) Redactor {
+	return func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	}
+}
+
+// urlCredentials matches the user info of URLs such as
+// postgres://app:s3cret@db:5432/orders.
+var urlCredentials = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)([^/\s:@]+):([^/\s@]+)@`)
+
+// RedactURLPasswords masks the passwords of URLs in messages, such as the
+// DSN in "dial postgres://app:s3cret@db:5432/orders: connection refused".
+func RedactURLPasswords() Redactor {
+	return func(s string) string {
+		return urlCredentials.ReplaceAllStringFunc(s, func(match string) string {
+			sub := urlCredentials.FindStringSubmatch(match)
+			return sub[1] + url.UserPassword(sub[2], "xxxxx").String() + "@"
+		})
+	}
+}
+
+// RedactKeyValues masks the values of key=value pairs with one of keys, such
+// as the password of the DSN "host=db user=app password=s3cret".
+func RedactKeyValues(keys ...string) Redactor {
+	alternatives := ""
+	for i, key := range keys {
+		if i > 0 {
+			alternatives += "|"
+		}
+		alternatives += regexp.QuoteMeta(key)
+	}
+	re := regexp.MustCompile(`(?i)\b(` + alternatives + `)=("[^"]*"|'[^']*'|[^\s;&,]*)`)
+	return RedactPattern(re, "${1}=xxxxx")
+}
+
+func (h *HTTPHandler) redact(s string) string {
+	for _, redact := range h.redactors {
+		s = redact(s)
+	}
+	return s
+}
+
+// fullDetail reports whether r may see the full responses.
+func (h *HTTPHandler) fullDetail(r *http.Request) bool {
+	return h.detailAuth == nil || h.detailAuth.Authenticate(r)
+}
+
+// viewChecks returns the checks served to r: none unless r may see full
+// responses, and redacted otherwise. checks itself is not modified.
+func (h *HTTPHandler) viewChecks(r *http.Request, checks map[string]CheckResult) map[string]CheckResult {
+	if !h.fullDetail(r) {
+		return nil
+	}
+	if len(h.redactors) == 0 || checks == nil {
+		return checks
+	}
+
+	redacted := make(map[string]CheckResult, len(checks))
+	for name, result := range checks {
+		result.Message = h.redact(result.Message)
+		result.Error = h.redact(result.Error)
+		redacted[name] = result
+	}
+	return redacted
+}
+
+func (h *HTTPHandler) livenessView(r *http.Request, resp *LivenessResponse) *LivenessResponse {
+	view := *resp
+	view.Checks = h.viewChecks(r, resp.Checks)
+	return &view
+}
+
+func (h *HTTPHandler) startupView(r *http.Request, resp *StartupResponse) *StartupResponse {
+	view := *resp
+	view.Tasks = h.viewChecks(r, resp.Tasks)
+	return &view
+}
+
+func (h *HTTPHandler) readinessView(r *http.Request, resp *ReadinessResponse) *ReadinessResponse {
+	view := *resp
+	view.Checks = h.viewChecks(r, resp.Checks)
+	if view.Checks == nil {
+		// Checks is always present in readiness responses.
+		view.Checks = map[string]CheckResult{}
+		view.FailedChecks = nil
+	}
+	return &view
+}
+
+func (h *HTTPHandler) statusView(r *http.Request, resp *StatusResponse) *StatusResponse {
+	if h.fullDetail(r) {
+		return resp
+	}
+
+	return &StatusResponse{
+		ServiceName:   resp.ServiceName,
+		StartTime:     resp.StartTime,
+		UptimeSeconds: resp.UptimeSeconds,
+		Environment:   resp.Environment,
+	}
+}
@@ -0,0 +1,197 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactors(t *testing.T) {
+	tests := []struct {
+		name     string
+		redactor Redactor
+		input    string
+		want     string
+	}{
+		{
+			name:     "URL password",
+			redactor: RedactURLPasswords(),
+			input:    "dial postgres://app:s3cret@db:5432/orders: connection refused",
+			want:     "dial postgres://app:xxxxx@db:5432/orders: connection refused",
+		},
+		{
+			name:     "several URLs",
+			redactor: RedactURLPasswords(),
+			input:    "redis://:pw@cache:6379 and amqp://guest:guest@mq/",
+			want:     "redis://:pw@cache:6379 and amqp://guest:xxxxx@mq/",
+		},
+		{
+			name:     "URL without password",
+			redactor: RedactURLPasswords(),
+			input:    "GET https://api.example.org/v1: 502",
+			want:     "GET https://api.example.org/v1: 502",
+		},
+		{
+			name:     "key values",
+			redactor: RedactKeyValues("password", "pwd"),
+			input:    "host=db user=app password=s3cret dbname=orders",
+			want:     "host=db user=app password=xxxxx dbname=orders",
+		},
+		{
+			name:     "key values quoted and case insensitive",
+			redactor: RedactKeyValues("password", "pwd"),
+			input:    `Server=db;PWD='s3 cret';Database=orders`,
+			want:     `Server=db;PWD=xxxxx;Database=orders`,
+		},
+		{
+			name:     "pattern",
+			redactor: RedactPattern(regexp.MustCompile(`token [a-z0-9]+`), "token [redacted]"),
+			input:    "auth failed for token abc123",
+			want:     "auth failed for token [redacted]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.redactor(tt.input))
+		})
+	}
+}
+
+func TestHTTPHandler_DetailLevels(t *testing.T) {
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+	bs.GitCommit = "abc123"
+	bs.Hostname = "orders-7d9f"
+	bs.Dependencies = []Dependency{{Name: "postgres", Status: "connected", Version: "16.2"}}
+	bs.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		return errors.New("dial postgres://app:s3cret@db:5432/orders: connection refused")
+	}), WithCriticality(CriticalityNonCritical))
+	bs.RegisterLivenessChecker(NewChecker("deadlock", func(ctx context.Context) error { return nil }))
+
+	handler := NewHTTPHandler(bs,
+		WithDetailAuthorization(BearerToken("secret")),
+		WithRedaction(RedactURLPasswords()),
+	)
+
+	serve := func(path string, authorized bool, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authorized {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("public readiness", func(t *testing.T) {
+		rec := serve("/health/ready", false, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp ReadinessResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Ready)
+		assert.Equal(t, HealthStatusDegraded, resp.Status)
+		assert.Empty(t, resp.Checks)
+		assert.NotContains(t, rec.Body.String(), "postgres")
+	})
+
+	t.Run("public readiness ignores filters", func(t *testing.T) {
+		for _, path := range []string{"/health/ready/database", "/health/ready/billing", "/health/ready?exclude=database"} {
+			rec := serve(path, false, "")
+			require.Equal(t, http.StatusOK, rec.Code, path)
+
+			var resp ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), path)
+			assert.Equal(t, HealthStatusDegraded, resp.Status, path)
+			assert.Empty(t, resp.Checks, path)
+		}
+	})
+
+	t.Run("authorized readiness filters", func(t *testing.T) {
+		rec := serve("/health/ready/billing", true, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = serve("/health/ready?exclude=database", true, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp ReadinessResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, HealthStatusHealthy, resp.Status)
+	})
+
+	t.Run("authorized readiness", func(t *testing.T) {
+		rec := serve("/health/ready", true, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp ReadinessResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "dial postgres://app:xxxxx@db:5432/orders: connection refused", resp.Checks["database"].Error)
+		assert.NotContains(t, rec.Body.String(), "s3cret")
+	})
+
+	t.Run("public liveness and startup", func(t *testing.T) {
+		rec := serve("/health/live", false, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "deadlock")
+
+		rec = serve("/health/live", true, "")
+		assert.Contains(t, rec.Body.String(), "deadlock")
+	})
+
+	t.Run("public status", func(t *testing.T) {
+		rec := serve("/status", false, "")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp StatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "test-service", resp.ServiceName)
+		assert.Empty(t, resp.Version)
+		assert.Empty(t, resp.GitCommit)
+		assert.Empty(t, resp.Hostname)
+		assert.Empty(t, resp.Dependencies)
+	})
+
+	t.Run("authorized status", func(t *testing.T) {
+		var resp StatusResponse
+		require.NoError(t, json.Unmarshal(serve("/status", true, "").Body.Bytes(), &resp))
+		assert.Equal(t, "1.0.0", resp.Version)
+		assert.Equal(t, "abc123", resp.GitCommit)
+		assert.Equal(t, "orders-7d9f", resp.Hostname)
+		assert.Equal(t, bs.Dependencies, resp.Dependencies)
+	})
+
+	t.Run("other formats", func(t *testing.T) {
+		rec := serve("/health/ready", false, ContentTypeHealthJSON)
+		assert.Equal(t, `{"status":"warn"}`+"\n", rec.Body.String())
+
+		rec = serve("/health/ready?verbose", false, "")
+		assert.Equal(t, "readiness check passed\n", rec.Body.String())
+
+		rec = serve("/health/ready?verbose", true, "")
+		assert.Equal(t, "[-]database failed: dial postgres://app:xxxxx@db:5432/orders: connection refused\nreadiness check passed\n", rec.Body.String())
+	})
+}
+
+func TestHTTPHandler_RedactsServerErrors(t *testing.T) {
+	server := &mockServer{
+		readinessFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+			return nil, errors.New("querying mysql://root:hunter2@db/app")
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(server, WithRedaction(RedactURLPasswords())).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "mysql://root:xxxxx@db/app")
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// StatusError is returned by the client when the server answers with a status
+// code the endpoint does not define, such as 404 or 500. Clients for other
+// protocols map their status codes onto HTTP ones and keep the original error
+// in Err.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	Header     http.Header
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned by the client when a response cannot be decoded.
+type DecodeError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// TransportError is returned by the client when the request could not be
+// completed, e.g. because the connection was refused or reset.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("executing request: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned by the client when the request did not complete
+// within the client timeout or the deadline of its context.
+type TimeoutError struct {
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("executing request: timed out: %v", e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports true, so that TimeoutError satisfies net.Error style checks.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+// requestError classifies an error returned by http.Client.Do or while
+// reading a response body as a *TimeoutError or a *TransportError.
+func requestError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &TimeoutError{Err: err}
+	}
+	return &TransportError{Err: err}
+}
@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		opts    []ClientOption
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name: "unexpected status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "no such endpoint", http.StatusNotFound)
+			},
+			check: func(t *testing.T, err error) {
+				var statusErr *StatusError
+				require.True(t, errors.As(err, &statusErr))
+				assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+				assert.Equal(t, "no such endpoint\n", statusErr.Body)
+				assert.EqualError(t, err, "unexpected status code 404: no such endpoint\n")
+			},
+		},
+		{
+			name: "invalid body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("<html>"))
+			},
+			check: func(t *testing.T, err error) {
+				var decodeErr *DecodeError
+				require.True(t, errors.As(err, &decodeErr))
+				assert.Equal(t, http.StatusServiceUnavailable, decodeErr.StatusCode)
+				assert.ErrorContains(t, err, "decoding response")
+			},
+		},
+		{
+			name: "client timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			opts: []ClientOption{WithTimeout(20 * time.Millisecond)},
+			check: func(t *testing.T, err error) {
+				var timeoutErr *TimeoutError
+				require.True(t, errors.As(err, &timeoutErr))
+				assert.True(t, timeoutErr.Timeout())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client, err := NewClient(server.URL, tt.opts...)
+			require.NoError(t, err)
+
+			_, err = client.GetReadiness(context.Background())
+			require.Error(t, err)
+			tt.check(t, err)
+		})
+	}
+}
+
+func TestClient_Errors_Transport(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	_, err = client.GetHealth(context.Background())
+	var transportErr *TransportError
+	require.True(t, errors.As(err, &transportErr))
+	assert.ErrorContains(t, err, "executing request")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetStatus(ctx)
+	require.True(t, errors.As(err, &transportErr))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_Errors_ContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetMetrics(ctx)
+	var timeoutErr *TimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_StatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/health/ready":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"ready":false,"timestamp":"2024-01-06T15:04:05Z","checks":{}}`))
+		case "/health":
+			w.Header().Set("Content-Type", ContentTypeHealthJSON)
+			_, _ = w.Write([]byte(`{"status":"pass"}`))
+		default:
+			_, _ = w.Write([]byte(`{"service_name":"test-service"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	readiness, err := client.GetReadiness(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, readiness.StatusCode)
+
+	healthResp, err := client.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, healthResp.StatusCode)
+
+	status, err := client.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status.StatusCode)
+}
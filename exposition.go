@@ -0,0 +1,214 @@
+package health
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ContentTypeText is the content type of the Prometheus text format.
+	ContentTypeText = "text/plain; version=0.0.4"
+	// ContentTypeOpenMetrics is the content type of the OpenMetrics 1.0 text
+	// format.
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// WriteText renders families in the Prometheus text exposition format
+// (version 0.0.4). Units, exemplars and created timestamps are not part of
+// that format and are omitted.
+func WriteText(w io.Writer, families []MetricFamily) error {
+	bw := bufio.NewWriter(w)
+
+	for _, f := range families {
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+
+		for _, m := range f.Metrics {
+			ts := ""
+			if !m.Timestamp.IsZero() {
+				ts = " " + strconv.FormatInt(m.Timestamp.UnixNano()/int64(time.Millisecond), 10)
+			}
+
+			switch {
+			case m.Histogram != nil:
+				for _, b := range histogramBuckets(m.Histogram) {
+					writeSample(bw, f.Name+"_bucket", m.Labels, LabelPair{"le", formatFloat(b.UpperBound)}, float64(b.Count), ts)
+				}
+				writeSample(bw, f.Name+"_sum", m.Labels, LabelPair{}, m.Histogram.Sum, ts)
+				writeSample(bw, f.Name+"_count", m.Labels, LabelPair{}, float64(m.Histogram.Count), ts)
+			case m.Summary != nil:
+				for _, q := range m.Summary.Quantiles {
+					writeSample(bw, f.Name, m.Labels, LabelPair{"quantile", formatFloat(q.Quantile)}, q.Value, ts)
+				}
+				writeSample(bw, f.Name+"_sum", m.Labels, LabelPair{}, m.Summary.Sum, ts)
+				writeSample(bw, f.Name+"_count", m.Labels, LabelPair{}, float64(m.Summary.Count), ts)
+			default:
+				writeSample(bw, f.Name, m.Labels, LabelPair{}, m.Value, ts)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// WriteOpenMetrics renders families in the OpenMetrics 1.0 text format,
+// including units, exemplars and created timestamps, terminated by # EOF.
+// Counter families are named without their _total suffix as the format
+// requires.
+func WriteOpenMetrics(w io.Writer, families []MetricFamily) error {
+	bw := bufio.NewWriter(w)
+
+	for _, f := range families {
+		name := f.Name
+		metricType := string(f.Type)
+		switch f.Type {
+		case MetricTypeCounter:
+			name = strings.TrimSuffix(name, "_total")
+		case MetricTypeUntyped, "":
+			metricType = "unknown"
+		}
+
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, metricType)
+		if f.Unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", name, f.Unit)
+		}
+		if f.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeLabelValue(f.Help))
+		}
+
+		for _, m := range f.Metrics {
+			ts := ""
+			if !m.Timestamp.IsZero() {
+				ts = " " + formatTimestamp(m.Timestamp)
+			}
+
+			switch {
+			case m.Histogram != nil:
+				for _, b := range histogramBuckets(m.Histogram) {
+					writeSample(bw, name+"_bucket", m.Labels, LabelPair{"le", formatFloat(b.UpperBound)}, float64(b.Count), ts+formatExemplar(b.Exemplar))
+				}
+				writeSample(bw, name+"_count", m.Labels, LabelPair{}, float64(m.Histogram.Count), ts)
+				writeSample(bw, name+"_sum", m.Labels, LabelPair{}, m.Histogram.Sum, ts)
+			case m.Summary != nil:
+				for _, q := range m.Summary.Quantiles {
+					writeSample(bw, name, m.Labels, LabelPair{"quantile", formatFloat(q.Quantile)}, q.Value, ts)
+				}
+				writeSample(bw, name+"_count", m.Labels, LabelPair{}, float64(m.Summary.Count), ts)
+				writeSample(bw, name+"_sum", m.Labels, LabelPair{}, m.Summary.Sum, ts)
+			case f.Type == MetricTypeCounter:
+				writeSample(bw, name+"_total", m.Labels, LabelPair{}, m.Value, ts+formatExemplar(m.Exemplar))
+			default:
+				writeSample(bw, name, m.Labels, LabelPair{}, m.Value, ts)
+			}
+
+			if !m.Created.IsZero() && (f.Type == MetricTypeCounter || f.Type == MetricTypeHistogram || f.Type == MetricTypeSummary) {
+				writeSampleValue(bw, name+"_created", m.Labels, LabelPair{}, formatTimestamp(m.Created), ts)
+			}
+		}
+	}
+
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// histogramBuckets returns the buckets of h, appending the +Inf bucket from
+// the total count if h does not include it.
+func histogramBuckets(h *HistogramValue) []Bucket {
+	buckets := h.Buckets
+	if n := len(buckets); n == 0 || !math.IsInf(buckets[n-1].UpperBound, 1) {
+		buckets = append(buckets[:n:n], Bucket{UpperBound: math.Inf(1), Count: h.Count})
+	}
+	return buckets
+}
+
+func writeSample(w *bufio.Writer, name string, labels []LabelPair, extra LabelPair, value float64, suffix string) {
+	writeSampleValue(w, name, labels, extra, formatFloat(value), suffix)
+}
+
+func writeSampleValue(w *bufio.Writer, name string, labels []LabelPair, extra LabelPair, value string, suffix string) {
+	w.WriteString(name)
+	if len(labels) > 0 || extra.Name != "" {
+		w.WriteByte('{')
+		writeLabels(w, labels, extra)
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(value)
+	w.WriteString(suffix)
+	w.WriteByte('\n')
+}
+
+func writeLabels(w *bufio.Writer, labels []LabelPair, extra LabelPair) {
+	for i, l := range labels {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		fmt.Fprintf(w, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+	}
+	if extra.Name != "" {
+		if len(labels) > 0 {
+			w.WriteByte(',')
+		}
+		fmt.Fprintf(w, "%s=\"%s\"", extra.Name, extra.Value)
+	}
+}
+
+func formatExemplar(e *Exemplar) string {
+	if e == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(" # {")
+	for i, l := range e.Labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", l.Name, escapeLabelValue(l.Value))
+	}
+	b.WriteString("} ")
+	b.WriteString(formatFloat(e.Value))
+	if !e.Timestamp.IsZero() {
+		b.WriteByte(' ')
+		b.WriteString(formatTimestamp(e.Timestamp))
+	}
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// formatTimestamp renders t as Unix seconds, as OpenMetrics requires.
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
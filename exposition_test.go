@@ -0,0 +1,154 @@
+package health
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	created := time.Unix(1700000000, 500000000)
+	exemplarAt := time.Unix(1700000100, 0)
+
+	tests := []struct {
+		name     string
+		families []MetricFamily
+		want     string
+	}{
+		{
+			name: "empty",
+			want: "# EOF\n",
+		},
+		{
+			name: "counter with exemplar and created",
+			families: []MetricFamily{{
+				Name: "http_requests_total",
+				Help: "Total number of HTTP requests",
+				Type: MetricTypeCounter,
+				Metrics: []Metric{{
+					Labels:  []LabelPair{{Name: "method", Value: "GET"}},
+					Value:   12,
+					Created: created,
+					Exemplar: &Exemplar{
+						Labels:    []LabelPair{{Name: "trace_id", Value: "abc"}},
+						Value:     1,
+						Timestamp: exemplarAt,
+					},
+				}},
+			}},
+			want: `# TYPE http_requests counter
+# HELP http_requests Total number of HTTP requests
+http_requests_total{method="GET"} 12 # {trace_id="abc"} 1 1700000100
+http_requests_created{method="GET"} 1700000000.5
+# EOF
+`,
+		},
+		{
+			name: "histogram with unit",
+			families: []MetricFamily{{
+				Name: "request_duration_seconds",
+				Type: MetricTypeHistogram,
+				Unit: "seconds",
+				Metrics: []Metric{{
+					Histogram: &HistogramValue{
+						Count: 3,
+						Sum:   1.5,
+						Buckets: []Bucket{
+							{UpperBound: 0.1, Count: 1, Exemplar: &Exemplar{
+								Labels: []LabelPair{{Name: "trace_id", Value: "def"}},
+								Value:  0.05,
+							}},
+							{UpperBound: math.Inf(1), Count: 3},
+						},
+					},
+				}},
+			}},
+			want: `# TYPE request_duration_seconds histogram
+# UNIT request_duration_seconds seconds
+request_duration_seconds_bucket{le="0.1"} 1 # {trace_id="def"} 0.05
+request_duration_seconds_bucket{le="+Inf"} 3
+request_duration_seconds_count 3
+request_duration_seconds_sum 1.5
+# EOF
+`,
+		},
+		{
+			name: "untyped and summary",
+			families: []MetricFamily{
+				{
+					Name:    "legacy_value",
+					Type:    MetricTypeUntyped,
+					Metrics: []Metric{{Value: 7, Timestamp: created}},
+				},
+				{
+					Name: "gc_seconds",
+					Help: `Pause "duration"`,
+					Type: MetricTypeSummary,
+					Metrics: []Metric{{
+						Summary: &SummaryValue{
+							Count:     2,
+							Sum:       0.3,
+							Quantiles: []Quantile{{Quantile: 0.5, Value: 0.1}},
+						},
+					}},
+				},
+			},
+			want: `# TYPE legacy_value unknown
+legacy_value 7 1700000000.5
+# TYPE gc_seconds summary
+# HELP gc_seconds Pause \"duration\"
+gc_seconds{quantile="0.5"} 0.1
+gc_seconds_count 2
+gc_seconds_sum 0.3
+# EOF
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := WriteOpenMetrics(&b, tt.families)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestWriteText_OmitsOpenMetricsFields(t *testing.T) {
+	families := []MetricFamily{{
+		Name: "jobs_total",
+		Type: MetricTypeCounter,
+		Unit: "jobs",
+		Metrics: []Metric{{
+			Value:     4,
+			Created:   time.Unix(1700000000, 0),
+			Timestamp: time.Unix(1700000000, 250000000),
+			Exemplar:  &Exemplar{Value: 1},
+		}},
+	}}
+
+	var b strings.Builder
+	require.NoError(t, WriteText(&b, families))
+
+	assert.Equal(t, "# TYPE jobs_total counter\njobs_total 4 1700000000250\n", b.String())
+}
+
+func TestRegistry_WriteOpenMetrics(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter(MetricOpts{Name: "jobs_total", Help: "Jobs"}).Inc()
+	reg.NewGauge(MetricOpts{Name: "queue_size_bytes", Unit: "bytes"}).Set(512)
+
+	var b strings.Builder
+	require.NoError(t, reg.WriteOpenMetrics(&b))
+
+	out := b.String()
+	assert.Contains(t, out, "# TYPE jobs counter\n# HELP jobs Jobs\njobs_total 1\njobs_created ")
+	assert.Contains(t, out, "# TYPE queue_size_bytes gauge\n# UNIT queue_size_bytes bytes\nqueue_size_bytes 512\n")
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+}
@@ -0,0 +1,155 @@
+package health
+
+import (
+	"strings"
+)
+
+// MetricFamilies is a set of parsed metric families with helpers to look up
+// individual series and samples.
+type MetricFamilies []MetricFamily
+
+// Family returns the family with the given name. Counter families are named
+// with their _total suffix.
+func (fs MetricFamilies) Family(name string) (MetricFamily, bool) {
+	for _, f := range fs {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return MetricFamily{}, false
+}
+
+// Metric returns the series of the named family whose labels are exactly
+// labels. A nil map matches the series without labels.
+func (fs MetricFamilies) Metric(name string, labels map[string]string) (Metric, bool) {
+	f, ok := fs.Family(name)
+	if !ok {
+		return Metric{}, false
+	}
+	return f.Metric(labels)
+}
+
+// Sample returns the value of a single sample as it appears in the exposition
+// format. Besides plain counters, gauges and untyped metrics, name may refer
+// to the _bucket, _sum and _count samples of histograms and summaries; the
+// bucket is then selected by the "le" label and a summary quantile by the
+// "quantile" label.
+func (fs MetricFamilies) Sample(name string, labels map[string]string) (float64, bool) {
+	if f, ok := fs.Family(name); ok {
+		if f.Type == MetricTypeSummary {
+			return summaryQuantile(f, labels)
+		}
+		if m, ok := f.Metric(labels); ok && m.Histogram == nil && m.Summary == nil {
+			return m.Value, true
+		}
+	}
+
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		base := strings.TrimSuffix(name, suffix)
+		if base == name {
+			continue
+		}
+		f, ok := fs.Family(base)
+		if !ok {
+			continue
+		}
+		switch f.Type {
+		case MetricTypeHistogram:
+			return histogramSample(f, suffix, labels)
+		case MetricTypeSummary:
+			if suffix == "_bucket" {
+				return 0, false
+			}
+			m, ok := f.Metric(labels)
+			if !ok || m.Summary == nil {
+				return 0, false
+			}
+			if suffix == "_sum" {
+				return m.Summary.Sum, true
+			}
+			return float64(m.Summary.Count), true
+		}
+	}
+	return 0, false
+}
+
+// Metric returns the series of f whose labels are exactly labels.
+func (f MetricFamily) Metric(labels map[string]string) (Metric, bool) {
+	for _, m := range f.Metrics {
+		if labelsEqual(m.Labels, labels) {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+func histogramSample(f MetricFamily, suffix string, labels map[string]string) (float64, bool) {
+	series, le := splitLabel(labels, "le")
+	m, ok := f.Metric(series)
+	if !ok || m.Histogram == nil {
+		return 0, false
+	}
+
+	switch suffix {
+	case "_sum":
+		return m.Histogram.Sum, le == ""
+	case "_count":
+		return float64(m.Histogram.Count), le == ""
+	}
+
+	upper, err := parseFloat(le)
+	if err != nil {
+		return 0, false
+	}
+	for _, b := range histogramBuckets(m.Histogram) {
+		if b.UpperBound == upper {
+			return float64(b.Count), true
+		}
+	}
+	return 0, false
+}
+
+func summaryQuantile(f MetricFamily, labels map[string]string) (float64, bool) {
+	series, quantile := splitLabel(labels, "quantile")
+	q, err := parseFloat(quantile)
+	if err != nil {
+		return 0, false
+	}
+	m, ok := f.Metric(series)
+	if !ok || m.Summary == nil {
+		return 0, false
+	}
+	for _, sq := range m.Summary.Quantiles {
+		if sq.Quantile == q {
+			return sq.Value, true
+		}
+	}
+	return 0, false
+}
+
+// splitLabel returns labels without name, and the value of name.
+func splitLabel(labels map[string]string, name string) (map[string]string, string) {
+	value, ok := labels[name]
+	if !ok {
+		return labels, ""
+	}
+	rest := make(map[string]string, len(labels)-1)
+	for k, v := range labels {
+		if k != name {
+			rest[k] = v
+		}
+	}
+	return rest, value
+}
+
+func labelsEqual(pairs []LabelPair, labels map[string]string) bool {
+	if len(pairs) != len(labels) {
+		return false
+	}
+	for _, l := range pairs {
+		if v, ok := labels[l.Name]; !ok || v != l.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const familiesFixture = `# TYPE http_requests_total counter
+http_requests_total{method="GET",status="200"} 12
+http_requests_total{method="POST",status="201"} 3
+# TYPE build_info gauge
+build_info 1
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/",le="0.1"} 1
+latency_seconds_bucket{route="/",le="0.5"} 2
+latency_seconds_bucket{route="/",le="+Inf"} 3
+latency_seconds_sum{route="/"} 3.25
+latency_seconds_count{route="/"} 3
+# TYPE gc_seconds summary
+gc_seconds{quantile="0.5"} 0.1
+gc_seconds{quantile="0.99"} 0.4
+gc_seconds_sum 0.9
+gc_seconds_count 4
+`
+
+func TestMetricFamilies_Sample(t *testing.T) {
+	families, err := ParseMetrics(strings.NewReader(familiesFixture))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		metric string
+		labels map[string]string
+		want   float64
+		wantOK bool
+	}{
+		{
+			name:   "counter",
+			metric: "http_requests_total",
+			labels: map[string]string{"method": "POST", "status": "201"},
+			want:   3,
+			wantOK: true,
+		},
+		{
+			name:   "partial label set does not match",
+			metric: "http_requests_total",
+			labels: map[string]string{"method": "POST"},
+		},
+		{
+			name:   "gauge without labels",
+			metric: "build_info",
+			want:   1,
+			wantOK: true,
+		},
+		{
+			name:   "histogram bucket",
+			metric: "latency_seconds_bucket",
+			labels: map[string]string{"route": "/", "le": "0.5"},
+			want:   2,
+			wantOK: true,
+		},
+		{
+			name:   "histogram inf bucket",
+			metric: "latency_seconds_bucket",
+			labels: map[string]string{"route": "/", "le": "+Inf"},
+			want:   3,
+			wantOK: true,
+		},
+		{
+			name:   "histogram sum",
+			metric: "latency_seconds_sum",
+			labels: map[string]string{"route": "/"},
+			want:   3.25,
+			wantOK: true,
+		},
+		{
+			name:   "histogram count",
+			metric: "latency_seconds_count",
+			labels: map[string]string{"route": "/"},
+			want:   3,
+			wantOK: true,
+		},
+		{
+			name:   "missing bucket",
+			metric: "latency_seconds_bucket",
+			labels: map[string]string{"route": "/", "le": "2"},
+		},
+		{
+			name:   "summary quantile",
+			metric: "gc_seconds",
+			labels: map[string]string{"quantile": "0.99"},
+			want:   0.4,
+			wantOK: true,
+		},
+		{
+			name:   "summary count",
+			metric: "gc_seconds_count",
+			want:   4,
+			wantOK: true,
+		},
+		{
+			name:   "unknown metric",
+			metric: "missing_total",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := families.Sample(tt.metric, tt.labels)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetricFamilies_Metric(t *testing.T) {
+	families, err := ParseMetrics(strings.NewReader(familiesFixture))
+	require.NoError(t, err)
+
+	m, ok := families.Metric("latency_seconds", map[string]string{"route": "/"})
+	require.True(t, ok)
+	require.NotNil(t, m.Histogram)
+	assert.Equal(t, uint64(3), m.Histogram.Count)
+
+	_, ok = families.Metric("latency_seconds", map[string]string{"route": "/other"})
+	assert.False(t, ok)
+
+	_, ok = families.Metric("missing", nil)
+	assert.False(t, ok)
+
+	f, ok := families.Family("gc_seconds")
+	require.True(t, ok)
+	assert.Equal(t, MetricTypeSummary, f.Type)
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"strings"
+)
+
+// CheckFilter selects the readiness checks to run, following the conventions
+// of the Kubernetes apiserver's /readyz endpoint. An empty Include selects
+// every check; Exclude removes checks from the selection.
+type CheckFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// ReadinessFilterer is implemented by servers that can run a subset of their
+// readiness checks. HTTPHandler uses it for the per-check readiness endpoint
+// and the include and exclude query parameters; other servers run all checks
+// and the handler filters the results.
+type ReadinessFilterer interface {
+	GetReadinessFiltered(ctx context.Context, filter CheckFilter) (*ReadinessResponse, error)
+}
+
+// matches reports whether the check called name is selected by f.
+func (f CheckFilter) matches(name string) bool {
+	for _, excluded := range f.Exclude {
+		if excluded == name {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, included := range f.Include {
+		if included == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (f CheckFilter) empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// apply returns the results of checks selected by f.
+func (f CheckFilter) apply(checks map[string]CheckResult) map[string]CheckResult {
+	filtered := make(map[string]CheckResult, len(checks))
+	for name, result := range checks {
+		if f.matches(name) {
+			filtered[name] = result
+		}
+	}
+	return filtered
+}
+
+// missing returns the included checks that are absent from checks.
+func (f CheckFilter) missing(checks map[string]CheckResult) []string {
+	var missing []string
+	for _, name := range f.Include {
+		if _, ok := checks[name]; !ok && f.matches(name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// parseCheckNames splits repeated, comma separated query values such as
+// ?include=db,cache&include=queue into check names.
+func parseCheckNames(values []string) []string {
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// filterReadiness narrows resp down to the checks selected by filter and
+// recomputes the overall status from them.
+func filterReadiness(resp *ReadinessResponse, filter CheckFilter) *ReadinessResponse {
+	checks := filter.apply(resp.Checks)
+	status := aggregateStatus(checks)
+
+	return &ReadinessResponse{
+		Ready:        status != HealthStatusUnhealthy,
+		Status:       status,
+		Timestamp:    resp.Timestamp,
+		Checks:       checks,
+		FailedChecks: failedChecks(checks),
+	}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter CheckFilter
+		check  string
+		want   bool
+	}{
+		{
+			name:   "empty filter",
+			filter: CheckFilter{},
+			check:  "database",
+			want:   true,
+		},
+		{
+			name:   "included",
+			filter: CheckFilter{Include: []string{"cache", "database"}},
+			check:  "database",
+			want:   true,
+		},
+		{
+			name:   "not included",
+			filter: CheckFilter{Include: []string{"cache"}},
+			check:  "database",
+			want:   false,
+		},
+		{
+			name:   "excluded",
+			filter: CheckFilter{Exclude: []string{"database"}},
+			check:  "database",
+			want:   false,
+		},
+		{
+			name:   "exclude wins over include",
+			filter: CheckFilter{Include: []string{"database"}, Exclude: []string{"database"}},
+			check:  "database",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matches(tt.check))
+		})
+	}
+}
+
+func TestParseCheckNames(t *testing.T) {
+	assert.Nil(t, parseCheckNames(nil))
+	assert.Equal(t,
+		[]string{"database", "cache", "queue"},
+		parseCheckNames([]string{"database, cache", "", "queue,"}),
+	)
+}
+
+func TestFilterReadiness(t *testing.T) {
+	now := time.Now()
+	resp := &ReadinessResponse{
+		Ready:     false,
+		Status:    HealthStatusUnhealthy,
+		Timestamp: now,
+		Checks: map[string]CheckResult{
+			"database": {Status: HealthStatusHealthy},
+			"cache":    {Status: HealthStatusUnhealthy, Error: "connection refused"},
+			"queue":    {Status: HealthStatusDegraded},
+		},
+		FailedChecks: []string{"cache"},
+	}
+
+	got := filterReadiness(resp, CheckFilter{Exclude: []string{"cache"}})
+
+	assert.Equal(t, &ReadinessResponse{
+		Ready:     true,
+		Status:    HealthStatusDegraded,
+		Timestamp: now,
+		Checks: map[string]CheckResult{
+			"database": {Status: HealthStatusHealthy},
+			"queue":    {Status: HealthStatusDegraded},
+		},
+	}, got)
+}
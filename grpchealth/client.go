@@ -0,0 +1,342 @@
+package grpchealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	health "github.com/fableford/fableford-health-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ErrUnsupported is returned by Client methods that grpc.health.v1 cannot
+// answer when the status extension is not enabled.
+var ErrUnsupported = errors.New("not supported by grpc.health.v1 without the status extension")
+
+type ClientOption func(*client)
+
+// WithStatusExtension makes the client call the status extension service
+// registered with RegisterStatusServer, which returns the same responses as
+// the HTTP endpoints.
+func WithStatusExtension() ClientOption {
+	return func(c *client) {
+		c.statusExtension = true
+	}
+}
+
+// WithCheckServices makes GetReadiness query each of the given service names
+// and report them as checks. Without the status extension, readiness
+// responses otherwise contain no checks.
+func WithCheckServices(services ...string) ClientOption {
+	return func(c *client) {
+		c.checkServices = append(c.checkServices, services...)
+	}
+}
+
+type client struct {
+	conn            grpc.ClientConnInterface
+	health          healthpb.HealthClient
+	statusExtension bool
+	checkServices   []string
+}
+
+// NewClient returns a health.Client that probes the gRPC service behind conn.
+// Without the status extension, responses are derived from grpc.health.v1
+// serving statuses: SERVING is reported as healthy, ready and started,
+// NOT_SERVING as unhealthy, and any answer at all as alive.
+func NewClient(conn grpc.ClientConnInterface, opts ...ClientOption) health.Client {
+	c := &client{
+		conn:   conn,
+		health: healthpb.NewHealthClient(conn),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *client) GetHealth(ctx context.Context) (*health.HealthResponse, error) {
+	if c.statusExtension {
+		var resp health.HealthResponse
+		if err := c.invokeJSON(ctx, "GetHealth", &resp); err != nil {
+			return nil, err
+		}
+		resp.StatusCode = statusCode(resp.Status != health.HealthStatusUnhealthy)
+		return &resp, nil
+	}
+
+	servingStatus, err := c.check(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &health.HealthResponse{
+		Status:     healthStatus(servingStatus),
+		Timestamp:  time.Now(),
+		StatusCode: statusCode(servingStatus == healthpb.HealthCheckResponse_SERVING),
+	}, nil
+}
+
+func (c *client) GetLiveness(ctx context.Context) (*health.LivenessResponse, error) {
+	if c.statusExtension {
+		var resp health.LivenessResponse
+		if err := c.invokeJSON(ctx, "GetLiveness", &resp); err != nil {
+			return nil, err
+		}
+		resp.StatusCode = statusCode(resp.Alive)
+		return &resp, nil
+	}
+
+	if _, err := c.check(ctx, ""); err != nil {
+		return nil, err
+	}
+
+	return &health.LivenessResponse{
+		Alive:      true,
+		Timestamp:  time.Now(),
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func (c *client) GetStartup(ctx context.Context) (*health.StartupResponse, error) {
+	if c.statusExtension {
+		var resp health.StartupResponse
+		if err := c.invokeJSON(ctx, "GetStartup", &resp); err != nil {
+			return nil, err
+		}
+		resp.StatusCode = statusCode(resp.Started)
+		return &resp, nil
+	}
+
+	servingStatus, err := c.check(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	started := servingStatus == healthpb.HealthCheckResponse_SERVING
+	return &health.StartupResponse{
+		Started:    started,
+		Timestamp:  time.Now(),
+		StatusCode: statusCode(started),
+	}, nil
+}
+
+func (c *client) GetReadiness(ctx context.Context) (*health.ReadinessResponse, error) {
+	if c.statusExtension {
+		var resp health.ReadinessResponse
+		if err := c.invokeJSON(ctx, "GetReadiness", &resp); err != nil {
+			return nil, err
+		}
+		resp.StatusCode = statusCode(resp.Ready)
+		return &resp, nil
+	}
+
+	servingStatus, err := c.check(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ready := servingStatus == healthpb.HealthCheckResponse_SERVING
+	resp := &health.ReadinessResponse{
+		Ready:      ready,
+		Status:     healthStatus(servingStatus),
+		Timestamp:  time.Now(),
+		Checks:     make(map[string]health.CheckResult, len(c.checkServices)),
+		StatusCode: statusCode(ready),
+	}
+
+	for _, service := range c.checkServices {
+		start := time.Now()
+		servingStatus, err := c.check(ctx, service)
+		result := health.CheckResult{
+			Status:    healthStatus(servingStatus),
+			Duration:  time.Since(start),
+			CheckedAt: &start,
+		}
+		if status.Code(err) == codes.NotFound {
+			result = health.CheckResult{Status: health.HealthStatusUnhealthy, Error: "unknown service"}
+		} else if err != nil {
+			return nil, err
+		}
+
+		resp.Checks[service] = result
+		if result.Status == health.HealthStatusUnhealthy {
+			resp.FailedChecks = append(resp.FailedChecks, service)
+		}
+	}
+	sort.Strings(resp.FailedChecks)
+
+	return resp, nil
+}
+
+// GetCheck returns the readiness check called name. With the status
+// extension it is taken from the full readiness response; otherwise name is
+// queried as a grpc.health.v1 service name.
+func (c *client) GetCheck(ctx context.Context, name string) (*health.CheckResult, error) {
+	if c.statusExtension {
+		resp, err := c.GetReadiness(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, ok := resp.Checks[name]
+		if !ok {
+			return nil, &health.DecodeError{StatusCode: resp.StatusCode, Err: fmt.Errorf("check %q missing from response", name)}
+		}
+		return &result, nil
+	}
+
+	start := time.Now()
+	servingStatus, err := c.check(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &health.CheckResult{
+		Status:    healthStatus(servingStatus),
+		Duration:  time.Since(start),
+		CheckedAt: &start,
+	}, nil
+}
+
+func (c *client) GetStatus(ctx context.Context) (*health.StatusResponse, error) {
+	if !c.statusExtension {
+		return nil, fmt.Errorf("GetStatus: %w", ErrUnsupported)
+	}
+
+	var resp health.StatusResponse
+	if err := c.invokeJSON(ctx, "GetStatus", &resp); err != nil {
+		return nil, err
+	}
+	resp.StatusCode = http.StatusOK
+	return &resp, nil
+}
+
+func (c *client) GetMetrics(ctx context.Context) (string, error) {
+	if !c.statusExtension {
+		return "", fmt.Errorf("GetMetrics: %w", ErrUnsupported)
+	}
+
+	body, err := c.invoke(ctx, "GetMetrics")
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (c *client) GetMetricFamilies(ctx context.Context) (health.MetricFamilies, error) {
+	metrics, err := c.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	families, err := health.ParseMetrics(strings.NewReader(metrics))
+	if err != nil {
+		return nil, &health.DecodeError{StatusCode: http.StatusOK, Err: fmt.Errorf("parsing metrics: %w", err)}
+	}
+
+	return families, nil
+}
+
+func (c *client) check(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	resp, err := c.health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, rpcError(err)
+	}
+	return resp.GetStatus(), nil
+}
+
+func (c *client) invoke(ctx context.Context, method string) ([]byte, error) {
+	out := new(wrapperspb.BytesValue)
+	if err := c.conn.Invoke(ctx, "/"+StatusServiceName+"/"+method, new(emptypb.Empty), out); err != nil {
+		return nil, rpcError(err)
+	}
+	return out.GetValue(), nil
+}
+
+func (c *client) invokeJSON(ctx context.Context, method string, v interface{}) error {
+	body, err := c.invoke(ctx, method)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return &health.DecodeError{Err: err}
+	}
+	return nil
+}
+
+// rpcError wraps err like the HTTP client does: exceeded deadlines are
+// reported as a *health.TimeoutError, other failures to reach the server as a
+// *health.TransportError, and answers other than OK as a *health.StatusError
+// carrying the equivalent HTTP status code.
+func rpcError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return &health.TransportError{Err: err}
+	}
+
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		return &health.TimeoutError{Err: err}
+	case codes.Unavailable, codes.Canceled:
+		return &health.TransportError{Err: err}
+	default:
+		return &health.StatusError{StatusCode: httpStatusCode(st.Code()), Body: st.Message(), Err: err}
+	}
+}
+
+// httpStatusCode maps a gRPC status code onto the HTTP status code with the
+// same meaning, as gRPC gateways do.
+func httpStatusCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode returns the HTTP status code the HTTP handler would serve a
+// probe with, so that StatusCode means the same for both clients.
+func statusCode(ok bool) int {
+	if ok {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+func healthStatus(servingStatus healthpb.HealthCheckResponse_ServingStatus) health.HealthStatus {
+	if servingStatus == healthpb.HealthCheckResponse_SERVING {
+		return health.HealthStatusHealthy
+	}
+	return health.HealthStatusUnhealthy
+}
@@ -0,0 +1,231 @@
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	health "github.com/fableford/fableford-health-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialServer serves bs over grpc.health.v1 and, if extension is set, the
+// status extension, and returns a connection to it.
+func dialServer(t *testing.T, bs health.Server, extension bool) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	healthpb.RegisterHealthServer(gs, NewServer(bs))
+	if extension {
+		RegisterStatusServer(gs, bs)
+	}
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestClient_HealthProtocol(t *testing.T) {
+	tests := []struct {
+		name           string
+		ready          bool
+		wantStatus     health.HealthStatus
+		wantStatusCode int
+	}{
+		{
+			name:           "serving",
+			ready:          true,
+			wantStatus:     health.HealthStatusHealthy,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "not serving",
+			ready:          false,
+			wantStatus:     health.HealthStatusUnhealthy,
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := newBaseServer()
+			if !tt.ready {
+				bs.RegisterStartupTask("migrations", func(ctx context.Context) error { return nil })
+			}
+			c := NewClient(dialServer(t, bs, false), WithCheckServices("database", "cache", "billing"))
+			ctx := context.Background()
+
+			healthResp, err := c.GetHealth(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, healthResp.Status)
+			assert.Equal(t, tt.wantStatusCode, healthResp.StatusCode)
+
+			liveness, err := c.GetLiveness(ctx)
+			require.NoError(t, err)
+			assert.True(t, liveness.Alive)
+			assert.Equal(t, http.StatusOK, liveness.StatusCode)
+
+			startup, err := c.GetStartup(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ready, startup.Started)
+			assert.Equal(t, tt.wantStatusCode, startup.StatusCode)
+
+			readiness, err := c.GetReadiness(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ready, readiness.Ready)
+			assert.Equal(t, tt.wantStatusCode, readiness.StatusCode)
+			assert.Equal(t, tt.wantStatus, readiness.Status)
+			assert.Equal(t, health.HealthStatusHealthy, readiness.Checks["database"].Status)
+			assert.Equal(t, health.HealthStatusUnhealthy, readiness.Checks["cache"].Status)
+			assert.Equal(t, "unknown service", readiness.Checks["billing"].Error)
+			assert.Equal(t, []string{"billing", "cache"}, readiness.FailedChecks)
+
+			check, err := c.GetCheck(ctx, "database")
+			require.NoError(t, err)
+			assert.Equal(t, health.HealthStatusHealthy, check.Status)
+
+			_, err = c.GetCheck(ctx, "billing")
+			var statusErr *health.StatusError
+			require.ErrorAs(t, err, &statusErr)
+			assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+			assert.Equal(t, codes.NotFound, status.Code(err))
+
+			_, err = c.GetStatus(ctx)
+			assert.ErrorIs(t, err, ErrUnsupported)
+
+			_, err = c.GetMetrics(ctx)
+			assert.ErrorIs(t, err, ErrUnsupported)
+		})
+	}
+}
+
+func TestClient_StatusExtension(t *testing.T) {
+	bs := newBaseServer()
+	bs.HealthFromReadiness = true
+	bs.Metrics.NewGauge(health.MetricOpts{Name: "queue_depth"}).Set(4)
+	c := NewClient(dialServer(t, bs, true), WithStatusExtension())
+	ctx := context.Background()
+
+	liveness, err := c.GetLiveness(ctx)
+	require.NoError(t, err)
+	assert.True(t, liveness.Alive)
+	assert.Equal(t, http.StatusOK, liveness.StatusCode)
+
+	startup, err := c.GetStartup(ctx)
+	require.NoError(t, err)
+	assert.True(t, startup.Started)
+	assert.Equal(t, http.StatusOK, startup.StatusCode)
+
+	readiness, err := c.GetReadiness(ctx)
+	require.NoError(t, err)
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, http.StatusOK, readiness.StatusCode)
+	assert.Equal(t, health.HealthStatusDegraded, readiness.Status)
+	assert.Equal(t, "connection refused", readiness.Checks["cache"].Error)
+
+	healthResp, err := c.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, health.HealthStatusDegraded, healthResp.Status)
+	assert.Equal(t, http.StatusOK, healthResp.StatusCode)
+
+	check, err := c.GetCheck(ctx, "cache")
+	require.NoError(t, err)
+	assert.Equal(t, health.HealthStatusUnhealthy, check.Status)
+
+	_, err = c.GetCheck(ctx, "billing")
+	var decodeErr *health.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, http.StatusOK, decodeErr.StatusCode)
+	assert.ErrorContains(t, err, `check "billing" missing from response`)
+
+	statusResp, err := c.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "test-service", statusResp.ServiceName)
+	assert.Equal(t, http.StatusOK, statusResp.StatusCode)
+
+	families, err := c.GetMetricFamilies(ctx)
+	require.NoError(t, err)
+	value, ok := families.Sample("queue_depth", nil)
+	require.True(t, ok)
+	assert.Equal(t, 4.0, value)
+}
+
+type failingServer struct {
+	health.Server
+}
+
+func (failingServer) GetStatus(ctx context.Context) (*health.StatusResponse, error) {
+	return nil, errors.New("status unavailable")
+}
+
+func (failingServer) GetReadiness(ctx context.Context) (*health.ReadinessResponse, error) {
+	return nil, errors.New("readiness unavailable")
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Run("server error", func(t *testing.T) {
+		c := NewClient(dialServer(t, failingServer{}, true), WithStatusExtension())
+
+		_, err := c.GetStatus(context.Background())
+		require.Error(t, err)
+		var statusErr *health.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+		assert.Equal(t, "unexpected status code 500: status unavailable", err.Error())
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("extension not registered", func(t *testing.T) {
+		c := NewClient(dialServer(t, newBaseServer(), false), WithStatusExtension())
+
+		_, err := c.GetHealth(context.Background())
+		require.Error(t, err)
+		var statusErr *health.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusNotImplemented, statusErr.StatusCode)
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		lis := bufconn.Listen(1024)
+		require.NoError(t, lis.Close())
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err = NewClient(conn).GetHealth(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "executing request")
+		var transportErr *health.TransportError
+		var timeoutErr *health.TimeoutError
+		assert.True(t, errors.As(err, &transportErr) || errors.As(err, &timeoutErr))
+	})
+}
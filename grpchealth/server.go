@@ -0,0 +1,262 @@
+// Package grpchealth exposes health.Server implementations over the gRPC
+// Health Checking Protocol (grpc.health.v1.Health).
+package grpchealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	health "github.com/fableford/fableford-health-go"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+// Server implements grpc.health.v1.Health on top of a health.Server. The
+// empty service name reports overall readiness; any other name reports the
+// readiness check of that name, or the checks mapped to it with WithService.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+
+	server        health.Server
+	services      map[string][]string
+	watchInterval time.Duration
+
+	mu       sync.RWMutex
+	shutdown bool
+
+	watchMu  sync.Mutex
+	watchers map[string]*watcher
+}
+
+// watcher polls the status of one service on behalf of every Watch stream of
+// that service, so the checks run once per interval however many clients
+// watch it.
+type watcher struct {
+	cancel      context.CancelFunc
+	subscribers map[chan healthpb.HealthCheckResponse_ServingStatus]struct{}
+	last        healthpb.HealthCheckResponse_ServingStatus
+	polled      bool
+}
+
+type Option func(*Server)
+
+// WithService maps a gRPC service name, typically fully qualified such as
+// "orders.v1.OrderService", to the readiness checks it depends on. The
+// service is serving while none of the checks is unhealthy.
+func WithService(service string, checks ...string) Option {
+	return func(s *Server) {
+		s.services[service] = checks
+	}
+}
+
+// WithWatchInterval sets how often Watch streams re-evaluate the service
+// status. It defaults to five seconds.
+func WithWatchInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.watchInterval = interval
+	}
+}
+
+func NewServer(server health.Server, opts ...Option) *Server {
+	s := &Server{
+		server:        server,
+		services:      make(map[string][]string),
+		watchInterval: defaultWatchInterval,
+		watchers:      make(map[string]*watcher),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Shutdown reports every service as NOT_SERVING from now on, so that clients
+// stop sending traffic while the process drains. Resume undoes it.
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdown = true
+}
+
+// Resume reverts Shutdown.
+func (s *Server) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shutdown = false
+}
+
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	servingStatus, err := s.servingStatus(ctx, req.GetService())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "getting readiness: %v", err)
+	}
+	if servingStatus == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	return &healthpb.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch sends the status of the requested service immediately and again
+// whenever it changes. Unknown services are reported as SERVICE_UNKNOWN and
+// keep being watched in case they appear later. Streams watching the same
+// service share a single poller.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	updates, unsubscribe := s.subscribe(req.GetService())
+	defer unsubscribe()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case servingStatus := <-updates:
+			if servingStatus == last {
+				continue
+			}
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return status.Errorf(codes.Canceled, "sending status: %v", err)
+			}
+			last = servingStatus
+		}
+	}
+}
+
+// subscribe returns a channel that receives the status of service after
+// every poll, starting with the latest one if known. The poller of the
+// service is started with its first subscriber and stopped by the
+// unsubscribe function of its last.
+func (s *Server) subscribe(service string) (<-chan healthpb.HealthCheckResponse_ServingStatus, func()) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	w, ok := s.watchers[service]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		w = &watcher{
+			cancel:      cancel,
+			subscribers: make(map[chan healthpb.HealthCheckResponse_ServingStatus]struct{}),
+		}
+		s.watchers[service] = w
+		go s.poll(ctx, service, w)
+	}
+
+	updates := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+	w.subscribers[updates] = struct{}{}
+	if w.polled {
+		updates <- w.last
+	}
+
+	return updates, func() {
+		s.watchMu.Lock()
+		defer s.watchMu.Unlock()
+
+		delete(w.subscribers, updates)
+		if len(w.subscribers) == 0 && s.watchers[service] == w {
+			w.cancel()
+			delete(s.watchers, service)
+		}
+	}
+}
+
+// poll evaluates the status of service every watch interval until ctx is
+// cancelled and publishes it to the subscribers of w.
+func (s *Server) poll(ctx context.Context, service string, w *watcher) {
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	for {
+		servingStatus, err := s.servingStatus(ctx, service)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		s.publish(w, servingStatus)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish hands servingStatus to every subscriber of w, replacing a status
+// the subscriber has not received yet.
+func (s *Server) publish(w *watcher, servingStatus healthpb.HealthCheckResponse_ServingStatus) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	w.last, w.polled = servingStatus, true
+	for updates := range w.subscribers {
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- servingStatus
+	}
+}
+
+func (s *Server) servingStatus(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	checks, mapped := s.services[service]
+
+	var filter health.CheckFilter
+	switch {
+	case mapped:
+		filter.Include = checks
+	case service != "":
+		filter.Include = []string{service}
+	}
+	resp, err := s.readiness(ctx, filter)
+	if err != nil {
+		return healthpb.HealthCheckResponse_UNKNOWN, err
+	}
+
+	s.mu.RLock()
+	shutdown := s.shutdown
+	s.mu.RUnlock()
+
+	switch {
+	case service == "":
+		if shutdown || !resp.Ready {
+			return healthpb.HealthCheckResponse_NOT_SERVING, nil
+		}
+		return healthpb.HealthCheckResponse_SERVING, nil
+	case !mapped:
+		if _, ok := resp.Checks[service]; !ok {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, nil
+		}
+		checks = []string{service}
+	}
+
+	if shutdown {
+		return healthpb.HealthCheckResponse_NOT_SERVING, nil
+	}
+	for _, name := range checks {
+		result, ok := resp.Checks[name]
+		if !ok || result.Status == health.HealthStatusUnhealthy {
+			return healthpb.HealthCheckResponse_NOT_SERVING, nil
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING, nil
+}
+
+// readiness returns the readiness of the checks selected by filter, letting
+// the server skip the others if it implements health.ReadinessFilterer.
+func (s *Server) readiness(ctx context.Context, filter health.CheckFilter) (*health.ReadinessResponse, error) {
+	if filterer, ok := s.server.(health.ReadinessFilterer); ok && len(filter.Include) > 0 {
+		return filterer.GetReadinessFiltered(ctx, filter)
+	}
+	return s.server.GetReadiness(ctx)
+}
@@ -0,0 +1,254 @@
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	health "github.com/fableford/fableford-health-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves srv over an in-memory listener and returns a connection to it.
+func dial(t *testing.T, srv healthpb.HealthServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	healthpb.RegisterHealthServer(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+type errorServer struct {
+	health.Server
+}
+
+func (errorServer) GetReadiness(ctx context.Context) (*health.ReadinessResponse, error) {
+	return nil, errors.New("readiness unavailable")
+}
+
+func newBaseServer() *health.BaseServer {
+	bs := health.NewBaseServer("test-service", "1.0.0", "test")
+	bs.RegisterChecker(health.NewChecker("database", func(ctx context.Context) error {
+		return nil
+	}))
+	bs.RegisterChecker(health.NewChecker("cache", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}), health.WithCriticality(health.CriticalityNonCritical))
+	return bs
+}
+
+func TestServer_Check(t *testing.T) {
+	tests := []struct {
+		name       string
+		server     health.Server
+		opts       []Option
+		service    string
+		want       healthpb.HealthCheckResponse_ServingStatus
+		wantCode   codes.Code
+		wantErrMsg string
+	}{
+		{
+			name:    "overall readiness",
+			server:  newBaseServer(),
+			service: "",
+			want:    healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name: "overall not ready",
+			server: func() health.Server {
+				bs := newBaseServer()
+				bs.RegisterStartupTask("migrations", func(ctx context.Context) error { return nil })
+				return bs
+			}(),
+			service: "",
+			want:    healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "healthy check",
+			server:  newBaseServer(),
+			service: "database",
+			want:    healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:    "unhealthy check",
+			server:  newBaseServer(),
+			service: "cache",
+			want:    healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "mapped service",
+			server:  newBaseServer(),
+			opts:    []Option{WithService("orders.v1.OrderService", "database")},
+			service: "orders.v1.OrderService",
+			want:    healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:    "mapped service with failing check",
+			server:  newBaseServer(),
+			opts:    []Option{WithService("orders.v1.OrderService", "database", "cache")},
+			service: "orders.v1.OrderService",
+			want:    healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "mapped service with missing check",
+			server:  newBaseServer(),
+			opts:    []Option{WithService("orders.v1.OrderService", "queue")},
+			service: "orders.v1.OrderService",
+			want:    healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:       "unknown service",
+			server:     newBaseServer(),
+			service:    "billing",
+			wantCode:   codes.NotFound,
+			wantErrMsg: `unknown service "billing"`,
+		},
+		{
+			name:       "readiness error",
+			server:     errorServer{},
+			service:    "",
+			wantCode:   codes.Internal,
+			wantErrMsg: "readiness unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, NewServer(tt.server, tt.opts...))
+			client := healthpb.NewHealthClient(conn)
+
+			resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tt.service})
+
+			if tt.wantCode != codes.OK {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.GetStatus())
+		})
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	srv := NewServer(newBaseServer())
+	client := healthpb.NewHealthClient(dial(t, srv))
+	ctx := context.Background()
+
+	srv.Shutdown()
+	for _, service := range []string{"", "database"} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus(), service)
+	}
+
+	srv.Resume()
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func TestServer_Watch(t *testing.T) {
+	var failing atomic.Bool
+	bs := health.NewBaseServer("test-service", "1.0.0", "test")
+	bs.RegisterChecker(health.NewChecker("database", func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+
+	client := healthpb.NewHealthClient(dial(t, NewServer(bs, WithWatchInterval(10*time.Millisecond))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "database"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	failing.Store(true)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	failing.Store(false)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
+
+func TestServer_WatchUnknownService(t *testing.T) {
+	client := healthpb.NewHealthClient(dial(t, NewServer(newBaseServer(), WithWatchInterval(10*time.Millisecond))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "billing"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, resp.GetStatus())
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestServer_WatchSharesPoller(t *testing.T) {
+	var databaseCalls, cacheCalls atomic.Int32
+	bs := health.NewBaseServer("test-service", "1.0.0", "test")
+	bs.RegisterChecker(health.NewChecker("database", func(ctx context.Context) error {
+		databaseCalls.Add(1)
+		return nil
+	}))
+	bs.RegisterChecker(health.NewChecker("cache", func(ctx context.Context) error {
+		cacheCalls.Add(1)
+		return nil
+	}))
+
+	client := healthpb.NewHealthClient(dial(t, NewServer(bs, WithWatchInterval(time.Hour))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "database"})
+		require.NoError(t, err)
+
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	assert.Equal(t, int32(1), databaseCalls.Load())
+	assert.Zero(t, cacheCalls.Load())
+}
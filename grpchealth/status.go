@@ -0,0 +1,99 @@
+package grpchealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	health "github.com/fableford/fableford-health-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// StatusServiceName is the name of the status extension service. It mirrors
+// the HTTP endpoints: each method takes google.protobuf.Empty and returns a
+// google.protobuf.BytesValue holding the JSON body of the matching endpoint,
+// or the Prometheus text exposition for GetMetrics.
+const StatusServiceName = "fableford.health.v1.Status"
+
+// RegisterStatusServer registers the status extension service for server on
+// s. It complements grpc.health.v1 with the full responses of the Server
+// interface, so that a Client created with WithStatusExtension can serve
+// every method.
+func RegisterStatusServer(s grpc.ServiceRegistrar, server health.Server) {
+	s.RegisterService(&statusServiceDesc, server)
+}
+
+var statusServiceDesc = grpc.ServiceDesc{
+	ServiceName: StatusServiceName,
+	HandlerType: (*health.Server)(nil),
+	Methods: []grpc.MethodDesc{
+		statusMethod("GetHealth", func(ctx context.Context, s health.Server) ([]byte, error) {
+			return marshal(s.GetHealth(ctx))
+		}),
+		statusMethod("GetLiveness", func(ctx context.Context, s health.Server) ([]byte, error) {
+			return marshal(s.GetLiveness(ctx))
+		}),
+		statusMethod("GetStartup", func(ctx context.Context, s health.Server) ([]byte, error) {
+			prober, ok := s.(health.StartupProber)
+			if !ok {
+				return marshal(&health.StartupResponse{Started: true, Timestamp: time.Now()}, nil)
+			}
+			return marshal(prober.GetStartup(ctx))
+		}),
+		statusMethod("GetReadiness", func(ctx context.Context, s health.Server) ([]byte, error) {
+			return marshal(s.GetReadiness(ctx))
+		}),
+		statusMethod("GetStatus", func(ctx context.Context, s health.Server) ([]byte, error) {
+			return marshal(s.GetStatus(ctx))
+		}),
+		statusMethod("GetMetrics", func(ctx context.Context, s health.Server) ([]byte, error) {
+			metrics, err := s.GetMetrics(ctx)
+			return []byte(metrics), err
+		}),
+	},
+	Metadata: "fableford/health/v1/status.proto",
+}
+
+func statusMethod(name string, fn func(ctx context.Context, s health.Server) ([]byte, error)) grpc.MethodDesc {
+	fullMethod := "/" + StatusServiceName + "/" + name
+
+	handle := func(ctx context.Context, srv interface{}) (interface{}, error) {
+		body, err := fn(ctx, srv.(health.Server))
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return wrapperspb.Bytes(body), nil
+	}
+
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			if err := dec(new(emptypb.Empty)); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return handle(ctx, srv)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+			return interceptor(ctx, new(emptypb.Empty), info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				return handle(ctx, srv)
+			})
+		},
+	}
+}
+
+func marshal(v interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding response: %w", err)
+	}
+	return body, nil
+}
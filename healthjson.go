@@ -0,0 +1,197 @@
+package health
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ContentTypeHealthJSON is the media type of the health check response format
+// described in draft-inadarei-api-health-check.
+const ContentTypeHealthJSON = "application/health+json"
+
+const (
+	healthJSONPass = "pass"
+	healthJSONWarn = "warn"
+	healthJSONFail = "fail"
+)
+
+// HealthJSONResponse is a response in the application/health+json format.
+type HealthJSONResponse struct {
+	Status      string                       `json:"status"`
+	Version     string                       `json:"version,omitempty"`
+	ReleaseID   string                       `json:"releaseId,omitempty"`
+	Notes       []string                     `json:"notes,omitempty"`
+	Output      string                       `json:"output,omitempty"`
+	ServiceID   string                       `json:"serviceId,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Checks      map[string][]HealthJSONCheck `json:"checks,omitempty"`
+}
+
+// HealthJSONCheck is a single entry of HealthJSONResponse.Checks.
+type HealthJSONCheck struct {
+	ComponentID       string      `json:"componentId,omitempty"`
+	ComponentType     string      `json:"componentType,omitempty"`
+	ObservedValue     interface{} `json:"observedValue,omitempty"`
+	ObservedUnit      string      `json:"observedUnit,omitempty"`
+	Status            string      `json:"status"`
+	AffectedEndpoints []string    `json:"affectedEndpoints,omitempty"`
+	Time              *time.Time  `json:"time,omitempty"`
+	Output            string      `json:"output,omitempty"`
+}
+
+// newHealthJSONResponse builds a health+json response from an overall status
+// and the check results that led to it.
+func newHealthJSONResponse(status HealthStatus, checks map[string]CheckResult) *HealthJSONResponse {
+	resp := &HealthJSONResponse{Status: healthJSONStatus(status)}
+	if len(checks) == 0 {
+		return resp
+	}
+
+	resp.Checks = make(map[string][]HealthJSONCheck, len(checks))
+	for name, result := range checks {
+		key := name
+		if result.Measurement != "" && !strings.Contains(name, ":") {
+			key = name + ":" + result.Measurement
+		}
+
+		check := HealthJSONCheck{
+			ComponentType:     result.ComponentType,
+			ObservedValue:     result.ObservedValue,
+			ObservedUnit:      result.ObservedUnit,
+			Status:            healthJSONStatus(result.Status),
+			AffectedEndpoints: result.AffectedEndpoints,
+			Time:              result.CheckedAt,
+		}
+		switch {
+		case result.Error != "":
+			check.Output = result.Error
+		case result.Status != HealthStatusHealthy:
+			check.Output = result.Message
+		}
+		resp.Checks[key] = append(resp.Checks[key], check)
+	}
+	return resp
+}
+
+func healthJSONStatus(status HealthStatus) string {
+	switch status {
+	case HealthStatusHealthy:
+		return healthJSONPass
+	case HealthStatusDegraded:
+		return healthJSONWarn
+	default:
+		return healthJSONFail
+	}
+}
+
+func statusFromHealthJSON(status string) HealthStatus {
+	switch strings.ToLower(status) {
+	case healthJSONPass, "ok", "up":
+		return HealthStatusHealthy
+	case healthJSONWarn:
+		return HealthStatusDegraded
+	default:
+		return HealthStatusUnhealthy
+	}
+}
+
+// checkResults converts the checks of r back into check results keyed by
+// component name. Entries sharing a key are told apart by their component ID.
+func (r *HealthJSONResponse) checkResults() map[string]CheckResult {
+	if len(r.Checks) == 0 {
+		return nil
+	}
+
+	results := make(map[string]CheckResult)
+	for key, checks := range r.Checks {
+		name, measurement, _ := strings.Cut(key, ":")
+		for i, check := range checks {
+			result := CheckResult{
+				Status:            statusFromHealthJSON(check.Status),
+				ObservedValue:     check.ObservedValue,
+				ObservedUnit:      check.ObservedUnit,
+				Measurement:       measurement,
+				ComponentType:     check.ComponentType,
+				AffectedEndpoints: check.AffectedEndpoints,
+				CheckedAt:         check.Time,
+			}
+			if result.Status == HealthStatusHealthy {
+				result.Message = check.Output
+			} else {
+				result.Error = check.Output
+			}
+
+			resultName := name
+			if len(checks) > 1 {
+				id := check.ComponentID
+				if id == "" {
+					id = fmt.Sprint(i)
+				}
+				resultName = name + "/" + id
+			}
+			results[resultName] = result
+		}
+	}
+	return results
+}
+
+func (r *HealthJSONResponse) healthResponse() *HealthResponse {
+	return &HealthResponse{
+		Status:    statusFromHealthJSON(r.Status),
+		Timestamp: time.Now(),
+	}
+}
+
+func (r *HealthJSONResponse) livenessResponse() *LivenessResponse {
+	return &LivenessResponse{
+		Alive:     statusFromHealthJSON(r.Status) != HealthStatusUnhealthy,
+		Timestamp: time.Now(),
+		Checks:    r.checkResults(),
+	}
+}
+
+func (r *HealthJSONResponse) startupResponse() *StartupResponse {
+	return &StartupResponse{
+		Started:   statusFromHealthJSON(r.Status) != HealthStatusUnhealthy,
+		Timestamp: time.Now(),
+		Tasks:     r.checkResults(),
+	}
+}
+
+func (r *HealthJSONResponse) readinessResponse() *ReadinessResponse {
+	status := statusFromHealthJSON(r.Status)
+	checks := r.checkResults()
+
+	var failed []string
+	for name, result := range checks {
+		if result.Status == HealthStatusUnhealthy {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+
+	return &ReadinessResponse{
+		Ready:        status != HealthStatusUnhealthy,
+		Status:       status,
+		Timestamp:    time.Now(),
+		Checks:       checks,
+		FailedChecks: failed,
+	}
+}
+
+func isHealthJSON(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == ContentTypeHealthJSON
+}
+
+func decodeHealthJSON(resp *http.Response) (*HealthJSONResponse, error) {
+	var doc HealthJSONResponse
+	if err := decodeJSON(resp, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
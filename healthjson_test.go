@@ -0,0 +1,129 @@
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthJSONResponse(t *testing.T) {
+	checkedAt := time.Date(2024, 1, 6, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status HealthStatus
+		checks map[string]CheckResult
+		want   *HealthJSONResponse
+	}{
+		{
+			name:   "no checks",
+			status: HealthStatusHealthy,
+			want:   &HealthJSONResponse{Status: "pass"},
+		},
+		{
+			name:   "checks keyed by component and measurement",
+			status: HealthStatusDegraded,
+			checks: map[string]CheckResult{
+				"database": {
+					Status:            HealthStatusHealthy,
+					Message:           "connected",
+					ObservedValue:     0.012,
+					ObservedUnit:      "s",
+					Measurement:       "responseTime",
+					ComponentType:     "datastore",
+					AffectedEndpoints: []string{"/orders"},
+					CheckedAt:         &checkedAt,
+				},
+				"cache": {
+					Status:  HealthStatusDegraded,
+					Message: "high eviction rate",
+				},
+				"queue:depth": {
+					Status:      HealthStatusUnhealthy,
+					Error:       "queue full",
+					Measurement: "ignored",
+				},
+			},
+			want: &HealthJSONResponse{
+				Status: "warn",
+				Checks: map[string][]HealthJSONCheck{
+					"database:responseTime": {{
+						ComponentType:     "datastore",
+						ObservedValue:     0.012,
+						ObservedUnit:      "s",
+						Status:            "pass",
+						AffectedEndpoints: []string{"/orders"},
+						Time:              &checkedAt,
+					}},
+					"cache": {{
+						Status: "warn",
+						Output: "high eviction rate",
+					}},
+					"queue:depth": {{
+						Status: "fail",
+						Output: "queue full",
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newHealthJSONResponse(tt.status, tt.checks))
+		})
+	}
+}
+
+func TestHealthJSONResponse_ReadinessResponse(t *testing.T) {
+	doc := &HealthJSONResponse{
+		Status: "fail",
+		Checks: map[string][]HealthJSONCheck{
+			"database:responseTime": {{
+				Status:        "pass",
+				ObservedValue: 12.5,
+				ObservedUnit:  "ms",
+			}},
+			"cpu:utilization": {
+				{ComponentID: "core-0", Status: "warn", Output: "busy"},
+				{ComponentID: "core-1", Status: "fail", Output: "throttled"},
+			},
+		},
+	}
+
+	resp := doc.readinessResponse()
+
+	assert.False(t, resp.Ready)
+	assert.Equal(t, HealthStatusUnhealthy, resp.Status)
+	assert.Equal(t, []string{"cpu/core-1"}, resp.FailedChecks)
+	assert.Equal(t, CheckResult{
+		Status:        HealthStatusHealthy,
+		ObservedValue: 12.5,
+		ObservedUnit:  "ms",
+		Measurement:   "responseTime",
+	}, resp.Checks["database"])
+	assert.Equal(t, HealthStatusDegraded, resp.Checks["cpu/core-0"].Status)
+	assert.Equal(t, "busy", resp.Checks["cpu/core-0"].Error)
+	assert.Equal(t, "throttled", resp.Checks["cpu/core-1"].Error)
+}
+
+func TestStatusFromHealthJSON(t *testing.T) {
+	tests := []struct {
+		status string
+		want   HealthStatus
+	}{
+		{status: "pass", want: HealthStatusHealthy},
+		{status: "ok", want: HealthStatusHealthy},
+		{status: "UP", want: HealthStatusHealthy},
+		{status: "warn", want: HealthStatusDegraded},
+		{status: "fail", want: HealthStatusUnhealthy},
+		{status: "down", want: HealthStatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.want, statusFromHealthJSON(tt.status))
+		})
+	}
+}
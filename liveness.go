@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Heartbeat is a liveness watchdog for long-running loops. The loop calls
+// Beat on every iteration; liveness fails once no beat has been seen for
+// longer than the heartbeat's maximum interval.
+type Heartbeat struct {
+	name        string
+	maxInterval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newHeartbeat(name string, maxInterval time.Duration) *Heartbeat {
+	return &Heartbeat{
+		name:        name,
+		maxInterval: maxInterval,
+		last:        time.Now(),
+	}
+}
+
+// Beat records that the monitored loop is making progress.
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last = time.Now()
+}
+
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+func (h *Heartbeat) Check(ctx context.Context) CheckResult {
+	h.mu.Lock()
+	since := time.Since(h.last)
+	h.mu.Unlock()
+
+	result := CheckResult{
+		Status:        HealthStatusHealthy,
+		Message:       fmt.Sprintf("last heartbeat %s ago", since.Round(time.Millisecond)),
+		ObservedValue: since.Seconds(),
+		ObservedUnit:  "s",
+	}
+	if since > h.maxInterval {
+		result.Status = HealthStatusUnhealthy
+		result.Error = fmt.Sprintf("no heartbeat for %s, maximum is %s", since.Round(time.Millisecond), h.maxInterval)
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseServer_GetLiveness_Checkers(t *testing.T) {
+	tests := []struct {
+		name      string
+		checker   Checker
+		opts      []CheckOption
+		wantAlive bool
+	}{
+		{
+			name:      "passing checker",
+			checker:   NewChecker("event_loop", func(ctx context.Context) error { return nil }),
+			wantAlive: true,
+		},
+		{
+			name:      "failing checker",
+			checker:   NewChecker("event_loop", func(ctx context.Context) error { return errors.New("deadlocked") }),
+			wantAlive: false,
+		},
+		{
+			name:      "failing non-critical checker",
+			checker:   NewChecker("event_loop", func(ctx context.Context) error { return errors.New("slow") }),
+			opts:      []CheckOption{WithCriticality(CriticalityNonCritical)},
+			wantAlive: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			server.RegisterLivenessChecker(tt.checker, tt.opts...)
+
+			resp, err := server.GetLiveness(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAlive, resp.Alive)
+			assert.Contains(t, resp.Checks, "event_loop")
+		})
+	}
+}
+
+func TestBaseServer_NewHeartbeat(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	hb := server.NewHeartbeat("worker_pool", 20*time.Millisecond)
+
+	resp, err := server.GetLiveness(context.Background())
+	require.NoError(t, err)
+	assert.True(t, resp.Alive)
+	assert.Equal(t, HealthStatusHealthy, resp.Checks["worker_pool"].Status)
+
+	time.Sleep(40 * time.Millisecond)
+
+	resp, err = server.GetLiveness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, resp.Alive)
+	assert.Contains(t, resp.Checks["worker_pool"].Error, "no heartbeat for")
+
+	hb.Beat()
+
+	resp, err = server.GetLiveness(context.Background())
+	require.NoError(t, err)
+	assert.True(t, resp.Alive)
+}
+
+func TestBaseServer_GetLiveness_DoesNotRunReadinessChecks(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	resp, err := server.GetLiveness(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, resp.Alive)
+	assert.Nil(t, resp.Checks)
+}
@@ -0,0 +1,418 @@
+package health
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+	MetricTypeSummary   MetricType = "summary"
+	MetricTypeUntyped   MetricType = "untyped"
+)
+
+// DefaultBuckets are the histogram buckets used when MetricOpts.Buckets is
+// empty. They suit request latencies measured in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// MetricFamily is a snapshot of all series sharing a metric name.
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Unit    string
+	Metrics []Metric
+}
+
+// Metric is a single series of a family. Value is used by counters, gauges
+// and untyped metrics; Histogram and Summary by their respective types.
+// Exemplar applies to counters. Created and Timestamp are zero when unknown.
+type Metric struct {
+	Labels    []LabelPair
+	Value     float64
+	Histogram *HistogramValue
+	Summary   *SummaryValue
+	Exemplar  *Exemplar
+	Created   time.Time
+	Timestamp time.Time
+}
+
+type LabelPair struct {
+	Name  string
+	Value string
+}
+
+type HistogramValue struct {
+	Count   uint64
+	Sum     float64
+	Buckets []Bucket
+}
+
+// Bucket holds the cumulative count of observations less than or equal to
+// UpperBound. The last bucket of a histogram has an UpperBound of +Inf.
+type Bucket struct {
+	UpperBound float64
+	Count      uint64
+	Exemplar   *Exemplar
+}
+
+// Exemplar links a sample to an individual event, typically a trace.
+type Exemplar struct {
+	Labels    []LabelPair
+	Value     float64
+	Timestamp time.Time
+}
+
+type SummaryValue struct {
+	Count     uint64
+	Sum       float64
+	Quantiles []Quantile
+}
+
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Collector produces metric families on demand.
+type Collector interface {
+	Collect() []MetricFamily
+}
+
+type MetricOpts struct {
+	Name string
+	Help string
+	// Unit is exposed in OpenMetrics output. The metric name, without a
+	// _total suffix, must end with "_" + Unit.
+	Unit    string
+	Labels  []string
+	Buckets []float64
+}
+
+// Registry holds the metrics exported by a service. It is safe for
+// concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	metrics    map[string]interface{}
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]interface{}),
+	}
+}
+
+// Register adds a collector whose families are included in every Gather.
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, c)
+}
+
+// NewCounter returns the counter registered under opts.Name, creating it if
+// needed. It panics if the name is invalid or already used by another type.
+func (r *Registry) NewCounter(opts MetricOpts) *Counter {
+	m := r.getOrCreate(opts, func() Collector {
+		return &Counter{vec: newMetricVec(opts, MetricTypeCounter)}
+	})
+	c, ok := m.(*Counter)
+	if !ok {
+		panic(fmt.Sprintf("health: metric %q already registered with a different type", opts.Name))
+	}
+	return c
+}
+
+// NewGauge returns the gauge registered under opts.Name, creating it if
+// needed. It panics if the name is invalid or already used by another type.
+func (r *Registry) NewGauge(opts MetricOpts) *Gauge {
+	m := r.getOrCreate(opts, func() Collector {
+		return &Gauge{vec: newMetricVec(opts, MetricTypeGauge)}
+	})
+	g, ok := m.(*Gauge)
+	if !ok {
+		panic(fmt.Sprintf("health: metric %q already registered with a different type", opts.Name))
+	}
+	return g
+}
+
+// NewHistogram returns the histogram registered under opts.Name, creating it
+// if needed. It panics if the name is invalid or already used by another type.
+func (r *Registry) NewHistogram(opts MetricOpts) *Histogram {
+	m := r.getOrCreate(opts, func() Collector {
+		buckets := opts.Buckets
+		if len(buckets) == 0 {
+			buckets = DefaultBuckets
+		}
+		buckets = append([]float64(nil), buckets...)
+		sort.Float64s(buckets)
+		return &Histogram{vec: newMetricVec(opts, MetricTypeHistogram), buckets: buckets}
+	})
+	h, ok := m.(*Histogram)
+	if !ok {
+		panic(fmt.Sprintf("health: metric %q already registered with a different type", opts.Name))
+	}
+	return h
+}
+
+func (r *Registry) getOrCreate(opts MetricOpts, create func() Collector) interface{} {
+	if !metricNameRE.MatchString(opts.Name) {
+		panic(fmt.Sprintf("health: invalid metric name %q", opts.Name))
+	}
+	for _, label := range opts.Labels {
+		if !metricNameRE.MatchString(label) || strings.Contains(label, ":") {
+			panic(fmt.Sprintf("health: invalid label name %q for metric %q", label, opts.Name))
+		}
+	}
+	if opts.Unit != "" && !strings.HasSuffix(strings.TrimSuffix(opts.Name, "_total"), "_"+opts.Unit) {
+		panic(fmt.Sprintf("health: metric %q must end with its unit %q", opts.Name, opts.Unit))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, ok := r.metrics[opts.Name]; ok {
+		return m
+	}
+
+	c := create()
+	r.metrics[opts.Name] = c
+	r.collectors = append(r.collectors, c)
+	return c
+}
+
+// Gather returns the families of all registered metrics and collectors,
+// sorted by name.
+func (r *Registry) Gather() []MetricFamily {
+	r.mu.RLock()
+	collectors := make([]Collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.RUnlock()
+
+	var families []MetricFamily
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].Name < families[j].Name
+	})
+	return families
+}
+
+// WriteText renders all registered metrics in the Prometheus text format.
+func (r *Registry) WriteText(w io.Writer) error {
+	return WriteText(w, r.Gather())
+}
+
+// WriteOpenMetrics renders all registered metrics in the OpenMetrics format.
+func (r *Registry) WriteOpenMetrics(w io.Writer) error {
+	return WriteOpenMetrics(w, r.Gather())
+}
+
+type Counter struct {
+	vec *metricVec
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the counter by v. It panics if v is negative.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.AddWithExemplar(v, nil, labelValues...)
+}
+
+// AddWithExemplar increases the counter by v and records exemplar, for
+// example {"trace_id": "..."}, as the counter's most recent exemplar.
+func (c *Counter) AddWithExemplar(v float64, exemplar map[string]string, labelValues ...string) {
+	if v < 0 {
+		panic("health: counter cannot decrease")
+	}
+	c.vec.update(labelValues, func(s *series) {
+		s.value += v
+		if exemplar != nil {
+			s.exemplar = newExemplar(exemplar, v)
+		}
+	})
+}
+
+func (c *Counter) Collect() []MetricFamily {
+	return []MetricFamily{c.vec.collect(func(s *series, m *Metric) {
+		m.Exemplar = s.exemplar
+		m.Created = s.created
+	})}
+}
+
+type Gauge struct {
+	vec *metricVec
+}
+
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.vec.update(labelValues, func(s *series) { s.value = v })
+}
+
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.vec.update(labelValues, func(s *series) { s.value += v })
+}
+
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+func (g *Gauge) Collect() []MetricFamily {
+	return []MetricFamily{g.vec.collect(nil)}
+}
+
+type Histogram struct {
+	vec     *metricVec
+	buckets []float64
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.ObserveWithExemplar(v, nil, labelValues...)
+}
+
+// ObserveWithExemplar records v and attaches exemplar to the bucket v falls
+// into.
+func (h *Histogram) ObserveWithExemplar(v float64, exemplar map[string]string, labelValues ...string) {
+	h.vec.update(labelValues, func(s *series) {
+		if s.counts == nil {
+			s.counts = make([]uint64, len(h.buckets))
+			s.exemplars = make([]*Exemplar, len(h.buckets)+1)
+		}
+		bucket := len(h.buckets)
+		for i := len(h.buckets) - 1; i >= 0 && v <= h.buckets[i]; i-- {
+			s.counts[i]++
+			bucket = i
+		}
+		s.count++
+		s.value += v
+		if exemplar != nil {
+			s.exemplars[bucket] = newExemplar(exemplar, v)
+		}
+	})
+}
+
+func (h *Histogram) Collect() []MetricFamily {
+	return []MetricFamily{h.vec.collect(func(s *series, m *Metric) {
+		hv := &HistogramValue{Count: s.count, Sum: s.value}
+		for i, upper := range h.buckets {
+			b := Bucket{UpperBound: upper}
+			if s.counts != nil {
+				b.Count = s.counts[i]
+				b.Exemplar = s.exemplars[i]
+			}
+			hv.Buckets = append(hv.Buckets, b)
+		}
+		inf := Bucket{UpperBound: math.Inf(1), Count: s.count}
+		if s.exemplars != nil {
+			inf.Exemplar = s.exemplars[len(h.buckets)]
+		}
+		hv.Buckets = append(hv.Buckets, inf)
+		m.Histogram = hv
+		m.Created = s.created
+	})}
+}
+
+func newExemplar(labels map[string]string, value float64) *Exemplar {
+	e := &Exemplar{Value: value, Timestamp: time.Now()}
+	for name, v := range labels {
+		e.Labels = append(e.Labels, LabelPair{Name: name, Value: v})
+	}
+	sort.Slice(e.Labels, func(i, j int) bool {
+		return e.Labels[i].Name < e.Labels[j].Name
+	})
+	return e
+}
+
+type series struct {
+	labelValues []string
+	created     time.Time
+	value       float64
+	count       uint64
+	counts      []uint64
+	exemplar    *Exemplar
+	exemplars   []*Exemplar
+}
+
+type metricVec struct {
+	opts       MetricOpts
+	metricType MetricType
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func newMetricVec(opts MetricOpts, metricType MetricType) *metricVec {
+	return &metricVec{
+		opts:       opts,
+		metricType: metricType,
+		series:     make(map[string]*series),
+	}
+}
+
+func (v *metricVec) update(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(v.opts.Labels) {
+		panic(fmt.Sprintf("health: metric %q expects %d label values, got %d",
+			v.opts.Name, len(v.opts.Labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...), created: time.Now()}
+		v.series[key] = s
+	}
+	fn(s)
+}
+
+func (v *metricVec) collect(fill func(s *series, m *Metric)) MetricFamily {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	family := MetricFamily{
+		Name: v.opts.Name,
+		Help: v.opts.Help,
+		Type: v.metricType,
+		Unit: v.opts.Unit,
+	}
+	for _, key := range keys {
+		s := v.series[key]
+		m := Metric{Value: s.value}
+		for i, name := range v.opts.Labels {
+			m.Labels = append(m.Labels, LabelPair{Name: name, Value: s.labelValues[i]})
+		}
+		if fill != nil {
+			fill(s, &m)
+		}
+		family.Metrics = append(family.Metrics, m)
+	}
+	return family
+}
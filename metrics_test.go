@@ -0,0 +1,225 @@
+package health
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(reg *Registry)
+		want  string
+	}{
+		{
+			name:  "empty registry",
+			setup: func(reg *Registry) {},
+			want:  "",
+		},
+		{
+			name: "counter with labels",
+			setup: func(reg *Registry) {
+				c := reg.NewCounter(MetricOpts{
+					Name:   "http_requests_total",
+					Help:   "Total number of HTTP requests",
+					Labels: []string{"method", "status"},
+				})
+				c.Inc("GET", "200")
+				c.Inc("GET", "200")
+				c.Add(2.5, "POST", "201")
+			},
+			want: `# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{method="GET",status="200"} 2
+http_requests_total{method="POST",status="201"} 2.5
+`,
+		},
+		{
+			name: "gauge operations",
+			setup: func(reg *Registry) {
+				g := reg.NewGauge(MetricOpts{Name: "queue_depth", Help: "Jobs waiting"})
+				g.Set(10)
+				g.Inc()
+				g.Dec()
+				g.Dec()
+				g.Add(-4)
+			},
+			want: `# HELP queue_depth Jobs waiting
+# TYPE queue_depth gauge
+queue_depth 5
+`,
+		},
+		{
+			name: "histogram",
+			setup: func(reg *Registry) {
+				h := reg.NewHistogram(MetricOpts{
+					Name:    "request_duration_seconds",
+					Help:    "Request latency",
+					Labels:  []string{"route"},
+					Buckets: []float64{0.5, 0.1},
+				})
+				h.Observe(0.05, "/health")
+				h.Observe(0.2, "/health")
+				h.Observe(3, "/health")
+			},
+			want: `# HELP request_duration_seconds Request latency
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{route="/health",le="0.1"} 1
+request_duration_seconds_bucket{route="/health",le="0.5"} 2
+request_duration_seconds_bucket{route="/health",le="+Inf"} 3
+request_duration_seconds_sum{route="/health"} 3.25
+request_duration_seconds_count{route="/health"} 3
+`,
+		},
+		{
+			name: "escaping",
+			setup: func(reg *Registry) {
+				reg.NewGauge(MetricOpts{
+					Name:   "build_info",
+					Help:   "Build \\ info\nsecond line",
+					Labels: []string{"version"},
+				}).Set(1, "v1 \"beta\"\n")
+			},
+			want: `# HELP build_info Build \\ info\nsecond line
+# TYPE build_info gauge
+build_info{version="v1 \"beta\"\n"} 1
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := NewRegistry()
+			tt.setup(reg)
+
+			var b strings.Builder
+			err := reg.WriteText(&b)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestRegistry_SameNameReturnsExistingMetric(t *testing.T) {
+	reg := NewRegistry()
+	first := reg.NewCounter(MetricOpts{Name: "events_total", Labels: []string{"kind"}})
+	second := reg.NewCounter(MetricOpts{Name: "events_total", Labels: []string{"kind"}})
+
+	first.Inc("a")
+	second.Inc("a")
+
+	assert.Same(t, first, second)
+	families := reg.Gather()
+	require.Len(t, families, 1)
+	assert.Equal(t, float64(2), families[0].Metrics[0].Value)
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(reg *Registry)
+	}{
+		{
+			name: "invalid metric name",
+			fn:   func(reg *Registry) { reg.NewCounter(MetricOpts{Name: "bad-name"}) },
+		},
+		{
+			name: "invalid label name",
+			fn:   func(reg *Registry) { reg.NewCounter(MetricOpts{Name: "ok_total", Labels: []string{"a:b"}}) },
+		},
+		{
+			name: "type mismatch",
+			fn: func(reg *Registry) {
+				reg.NewCounter(MetricOpts{Name: "things"})
+				reg.NewGauge(MetricOpts{Name: "things"})
+			},
+		},
+		{
+			name: "wrong label count",
+			fn: func(reg *Registry) {
+				reg.NewCounter(MetricOpts{Name: "things_total", Labels: []string{"kind"}}).Inc()
+			},
+		},
+		{
+			name: "negative counter increment",
+			fn: func(reg *Registry) {
+				reg.NewCounter(MetricOpts{Name: "things_total"}).Add(-1)
+			},
+		},
+		{
+			name: "name without unit suffix",
+			fn: func(reg *Registry) {
+				reg.NewHistogram(MetricOpts{Name: "request_duration", Unit: "seconds"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Panics(t, func() { tt.fn(NewRegistry()) })
+		})
+	}
+}
+
+func TestRegistry_ConcurrentUpdates(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounter(MetricOpts{Name: "ops_total", Labels: []string{"worker"}})
+	h := reg.NewHistogram(MetricOpts{Name: "op_seconds"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Inc("w")
+				h.Observe(0.01)
+				_ = reg.Gather()
+			}
+		}()
+	}
+	wg.Wait()
+
+	families := reg.Gather()
+	require.Len(t, families, 2)
+	assert.Equal(t, "op_seconds", families[0].Name)
+	assert.Equal(t, uint64(1000), families[0].Metrics[0].Histogram.Count)
+	assert.Equal(t, float64(1000), families[1].Metrics[0].Value)
+}
+
+func TestRegistry_Exemplars(t *testing.T) {
+	reg := NewRegistry()
+	c := reg.NewCounter(MetricOpts{Name: "jobs_total", Labels: []string{"queue"}})
+	h := reg.NewHistogram(MetricOpts{Name: "job_duration_seconds", Buckets: []float64{0.1, 1}})
+
+	c.AddWithExemplar(2, map[string]string{"trace_id": "abc", "span_id": "1"}, "emails")
+	c.Inc("emails")
+	h.ObserveWithExemplar(0.5, map[string]string{"trace_id": "def"})
+	h.ObserveWithExemplar(5, map[string]string{"trace_id": "ghi"})
+
+	families := reg.Gather()
+	require.Len(t, families, 2)
+
+	hist := families[0].Metrics[0].Histogram
+	require.Len(t, hist.Buckets, 3)
+	assert.Nil(t, hist.Buckets[0].Exemplar)
+	require.NotNil(t, hist.Buckets[1].Exemplar)
+	assert.Equal(t, []LabelPair{{Name: "trace_id", Value: "def"}}, hist.Buckets[1].Exemplar.Labels)
+	assert.Equal(t, 0.5, hist.Buckets[1].Exemplar.Value)
+	require.NotNil(t, hist.Buckets[2].Exemplar)
+	assert.Equal(t, uint64(2), hist.Buckets[2].Count)
+	assert.Equal(t, 5.0, hist.Buckets[2].Exemplar.Value)
+
+	counter := families[1].Metrics[0]
+	assert.Equal(t, 3.0, counter.Value)
+	assert.False(t, counter.Created.IsZero())
+	require.NotNil(t, counter.Exemplar)
+	assert.Equal(t, []LabelPair{{Name: "span_id", Value: "1"}, {Name: "trace_id", Value: "abc"}}, counter.Exemplar.Labels)
+	assert.Equal(t, 2.0, counter.Exemplar.Value)
+	assert.False(t, counter.Exemplar.Timestamp.IsZero())
+}
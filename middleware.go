@@ -0,0 +1,136 @@
+package health
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const unmatchedRoute = "unmatched"
+
+var responseSizeBuckets = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+
+type httpMetrics struct {
+	requests     *Counter
+	duration     *Histogram
+	inFlight     *Gauge
+	responseSize *Histogram
+}
+
+func newHTTPMetrics(reg *Registry) *httpMetrics {
+	labels := []string{"method", "route", "status"}
+
+	return &httpMetrics{
+		requests: reg.NewCounter(MetricOpts{
+			Name:   "http_requests_total",
+			Help:   "Total number of HTTP requests",
+			Labels: labels,
+		}),
+		duration: reg.NewHistogram(MetricOpts{
+			Name:   "http_request_duration_seconds",
+			Help:   "HTTP request latency",
+			Labels: labels,
+		}),
+		inFlight: reg.NewGauge(MetricOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served",
+		}),
+		responseSize: reg.NewHistogram(MetricOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Size of HTTP response bodies",
+			Labels:  labels,
+			Buckets: responseSizeBuckets,
+		}),
+	}
+}
+
+// NewMetricsMiddleware returns middleware that records request counts,
+// latencies, response sizes and in-flight requests into reg, labelled by
+// method, route pattern and status code. Pass BaseServer.Metrics to have the
+// numbers served on /metrics.
+func NewMetricsMiddleware(reg *Registry) func(http.Handler) http.Handler {
+	m := newHTTPMetrics(reg)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			muxPattern := servedPattern(next, r)
+			rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			status := strconv.Itoa(rec.status)
+			route := routePattern(r, muxPattern)
+			m.requests.Inc(r.Method, route, status)
+			m.duration.Observe(time.Since(start).Seconds(), r.Method, route, status)
+			m.responseSize.Observe(float64(rec.size), r.Method, route, status)
+		})
+	}
+}
+
+// servedPattern returns the pattern that next, if it is an http.ServeMux or
+// an HTTPHandler, matches r against. Request.Pattern would report it, but
+// only from Go 1.23 on.
+func servedPattern(next http.Handler, r *http.Request) string {
+	var mux *http.ServeMux
+	switch h := next.(type) {
+	case *http.ServeMux:
+		mux = h
+	case *HTTPHandler:
+		mux = h.mux
+	default:
+		return ""
+	}
+
+	_, pattern := mux.Handler(r)
+	return pattern
+}
+
+// routePattern returns the matched route pattern rather than the raw path so
+// that label cardinality stays bounded. Both chi routes and http.ServeMux
+// patterns are recognised; the method of a ServeMux pattern is dropped.
+func routePattern(r *http.Request, muxPattern string) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	if muxPattern != "" {
+		if _, path, ok := strings.Cut(muxPattern, " "); ok {
+			return path
+		}
+		return muxPattern
+	}
+	return unmatchedRoute
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
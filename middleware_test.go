@@ -0,0 +1,96 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsMiddleware(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	handler := NewHTTPHandler(server)
+
+	r := chi.NewRouter()
+	r.Use(NewMetricsMiddleware(server.Metrics))
+	handler.RegisterRoutes(r)
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("no such user"))
+	})
+
+	requests := []string{"/health", "/health", "/users/1", "/users/2", "/does-not-exist"}
+	for _, path := range requests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	metrics, err := server.GetMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/health",status="200"} 2`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/users/{id}",status="404"} 2`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, metrics, `http_request_duration_seconds_count{method="GET",route="/health",status="200"} 2`)
+	assert.Contains(t, metrics, `http_response_size_bytes_sum{method="GET",route="/users/{id}",status="404"} 24`)
+	assert.Contains(t, metrics, "http_requests_in_flight 0")
+}
+
+func TestNewMetricsMiddleware_ServeMux(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+
+	mux := http.NewServeMux()
+	NewHTTPHandler(server).RegisterServeMux(mux)
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	h := NewMetricsMiddleware(server.Metrics)(mux)
+
+	for _, path := range []string{"/health/live", "/users/1", "/users/2"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	metrics, err := server.GetMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/health/live",status="200"} 1`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/users/{id}",status="404"} 2`)
+}
+
+func TestNewMetricsMiddleware_HTTPHandler(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterChecker(NewChecker("database", func(ctx context.Context) error { return nil }))
+	h := NewMetricsMiddleware(server.Metrics)(NewHTTPHandler(server))
+
+	for _, path := range []string{"/health/ready/database", "/unknown"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	metrics, err := server.GetMetrics(context.Background())
+	require.NoError(t, err)
+
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="/health/ready/{check}",status="200"} 1`)
+	assert.Contains(t, metrics, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestNewMetricsMiddleware_InFlight(t *testing.T) {
+	reg := NewRegistry()
+	var inFlight float64
+
+	mw := NewMetricsMiddleware(reg)
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, f := range reg.Gather() {
+			if f.Name == "http_requests_in_flight" {
+				inFlight = f.Metrics[0].Value
+			}
+		}
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, float64(1), inFlight)
+}
@@ -0,0 +1,448 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseMetrics parses metrics in either the Prometheus text format or the
+// OpenMetrics text format. Families are returned in the order they appear.
+// Counter families are always named with their _total suffix so that both
+// formats produce the same names.
+func ParseMetrics(r io.Reader) (MetricFamilies, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+
+	// OpenMetrics timestamps are in seconds rather than milliseconds, so the
+	// format has to be known before the first sample is parsed.
+	p := &expositionParser{
+		families:    make(map[string]*parsedFamily),
+		openMetrics: bytes.HasSuffix(bytes.TrimSpace(data), []byte("# EOF")),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "# EOF" {
+			break
+		}
+
+		var err error
+		if strings.HasPrefix(line, "#") {
+			err = p.parseComment(line)
+		} else {
+			err = p.parseSample(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+
+	return p.result(), nil
+}
+
+type parsedFamily struct {
+	family  MetricFamily
+	series  map[string]int
+	counter bool
+}
+
+type expositionParser struct {
+	families    map[string]*parsedFamily
+	order       []string
+	openMetrics bool
+}
+
+func (p *expositionParser) family(name string) *parsedFamily {
+	f, ok := p.families[name]
+	if !ok {
+		f = &parsedFamily{
+			family: MetricFamily{Name: name, Type: MetricTypeUntyped},
+			series: make(map[string]int),
+		}
+		p.families[name] = f
+		p.order = append(p.order, name)
+	}
+	return f
+}
+
+func (p *expositionParser) parseComment(line string) error {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "#")), " ", 3)
+	if len(fields) < 2 {
+		return nil
+	}
+
+	name := fields[1]
+	rest := ""
+	if len(fields) == 3 {
+		rest = strings.TrimSpace(fields[2])
+	}
+
+	switch fields[0] {
+	case "HELP":
+		p.family(name).family.Help = unescapeHelp(rest)
+	case "UNIT":
+		p.family(name).family.Unit = rest
+	case "TYPE":
+		f := p.family(name)
+		switch rest {
+		case "counter":
+			f.family.Type = MetricTypeCounter
+			f.counter = true
+		case "gauge", "stateset", "info":
+			f.family.Type = MetricTypeGauge
+		case "histogram", "gaugehistogram":
+			f.family.Type = MetricTypeHistogram
+		case "summary":
+			f.family.Type = MetricTypeSummary
+		case "untyped", "unknown":
+			f.family.Type = MetricTypeUntyped
+		default:
+			return fmt.Errorf("unknown metric type %q for %s", rest, name)
+		}
+	}
+	return nil
+}
+
+func (p *expositionParser) parseSample(line string) error {
+	name, labels, rest, err := parseSeries(line)
+	if err != nil {
+		return err
+	}
+
+	var exemplar *Exemplar
+	if i := strings.Index(rest, " # "); i >= 0 {
+		exemplar, err = parseExemplar(rest[i+3:])
+		if err != nil {
+			return err
+		}
+		rest = rest[:i]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return fmt.Errorf("invalid sample %q", line)
+	}
+	value, err := parseFloat(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	var timestamp time.Time
+	if len(fields) == 2 {
+		timestamp, err = p.parseTimestamp(fields[1])
+		if err != nil {
+			return fmt.Errorf("invalid timestamp for %s: %w", name, err)
+		}
+	}
+
+	f, suffix := p.familyForSample(name)
+	m := f.metric(labels, f.family.Type == MetricTypeHistogram, f.family.Type == MetricTypeSummary)
+	m.Timestamp = timestamp
+
+	switch {
+	case suffix == "_created":
+		m.Created = time.Unix(0, int64(value*1e9))
+	case f.family.Type == MetricTypeHistogram:
+		switch suffix {
+		case "_bucket":
+			le, err := parseFloat(labelValue(labels, "le"))
+			if err != nil {
+				return fmt.Errorf("invalid le label for %s: %w", name, err)
+			}
+			m.Histogram.Buckets = append(m.Histogram.Buckets, Bucket{UpperBound: le, Count: uint64(value), Exemplar: exemplar})
+		case "_sum", "_gsum":
+			m.Histogram.Sum = value
+		case "_count", "_gcount":
+			m.Histogram.Count = uint64(value)
+		}
+	case f.family.Type == MetricTypeSummary:
+		switch suffix {
+		case "_sum":
+			m.Summary.Sum = value
+		case "_count":
+			m.Summary.Count = uint64(value)
+		default:
+			q, err := parseFloat(labelValue(labels, "quantile"))
+			if err != nil {
+				return fmt.Errorf("invalid quantile label for %s: %w", name, err)
+			}
+			m.Summary.Quantiles = append(m.Summary.Quantiles, Quantile{Quantile: q, Value: value})
+		}
+	default:
+		m.Value = value
+		m.Exemplar = exemplar
+	}
+	return nil
+}
+
+// familyForSample finds the family a sample belongs to, stripping the
+// type-specific suffix from its name.
+func (p *expositionParser) familyForSample(name string) (*parsedFamily, string) {
+	for _, suffix := range []string{"_bucket", "_count", "_sum", "_gcount", "_gsum", "_created", "_total", "_info"} {
+		base := strings.TrimSuffix(name, suffix)
+		if base == name {
+			continue
+		}
+		f, ok := p.families[base]
+		if !ok {
+			continue
+		}
+		switch f.family.Type {
+		case MetricTypeHistogram:
+			if suffix != "_total" && suffix != "_info" {
+				return f, suffix
+			}
+		case MetricTypeSummary:
+			if suffix == "_count" || suffix == "_sum" || suffix == "_created" {
+				return f, suffix
+			}
+		case MetricTypeCounter:
+			if suffix == "_total" || suffix == "_created" {
+				return f, suffix
+			}
+		case MetricTypeGauge:
+			if suffix == "_info" {
+				return f, suffix
+			}
+		}
+	}
+	if base := strings.TrimSuffix(name, "_created"); base != name {
+		if f, ok := p.families[base+"_total"]; ok && f.family.Type == MetricTypeCounter {
+			return f, "_created"
+		}
+	}
+	return p.family(name), ""
+}
+
+// metric returns the series of f with the given labels, ignoring the le and
+// quantile labels that distinguish histogram buckets and summary quantiles.
+func (f *parsedFamily) metric(labels []LabelPair, histogram, summary bool) *Metric {
+	var filtered []LabelPair
+	for _, l := range labels {
+		if (histogram && l.Name == "le") || (summary && l.Name == "quantile") {
+			continue
+		}
+		filtered = append(filtered, l)
+	}
+
+	key := labelsKey(filtered)
+	if i, ok := f.series[key]; ok {
+		return &f.family.Metrics[i]
+	}
+
+	m := Metric{Labels: filtered}
+	if histogram {
+		m.Histogram = &HistogramValue{}
+	}
+	if summary {
+		m.Summary = &SummaryValue{}
+	}
+	f.family.Metrics = append(f.family.Metrics, m)
+	f.series[key] = len(f.family.Metrics) - 1
+	return &f.family.Metrics[len(f.family.Metrics)-1]
+}
+
+func (p *expositionParser) parseTimestamp(s string) (time.Time, error) {
+	if p.openMetrics {
+		secs, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, int64(secs*1e9)), nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, ms*int64(time.Millisecond)), nil
+}
+
+func (p *expositionParser) result() MetricFamilies {
+	families := make(MetricFamilies, 0, len(p.order))
+	for _, name := range p.order {
+		f := p.families[name]
+		if f.counter && !strings.HasSuffix(f.family.Name, "_total") {
+			f.family.Name += "_total"
+		}
+		for _, m := range f.family.Metrics {
+			if m.Histogram != nil {
+				sort.SliceStable(m.Histogram.Buckets, func(i, j int) bool {
+					return m.Histogram.Buckets[i].UpperBound < m.Histogram.Buckets[j].UpperBound
+				})
+			}
+		}
+		families = append(families, f.family)
+	}
+	return families
+}
+
+// parseSeries splits a sample line into its metric name, labels and the
+// remainder holding the value, timestamp and exemplar.
+func parseSeries(line string) (string, []LabelPair, string, error) {
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return "", nil, "", fmt.Errorf("invalid sample %q", line)
+	}
+	name := line[:end]
+	if !metricNameRE.MatchString(name) {
+		return "", nil, "", fmt.Errorf("invalid metric name %q", name)
+	}
+
+	rest := line[end:]
+	var labels []LabelPair
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		labels, rest, err = parseLabels(rest)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("invalid labels for %s: %w", name, err)
+		}
+	}
+	return name, labels, strings.TrimSpace(rest), nil
+}
+
+// parseLabels parses a {name="value",...} label set at the start of s and
+// returns the remainder of s.
+func parseLabels(s string) ([]LabelPair, string, error) {
+	var labels []LabelPair
+	i := 1
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return nil, "", fmt.Errorf("unterminated label set")
+		}
+		if s[i] == '}' {
+			return labels, s[i+1:], nil
+		}
+
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 {
+			return nil, "", fmt.Errorf("missing '=' in label set")
+		}
+		name := strings.TrimSpace(s[i : i+eq])
+		i += eq + 1
+		if i >= len(s) || s[i] != '"' {
+			return nil, "", fmt.Errorf("label %s: value must be quoted", name)
+		}
+		i++
+
+		var value strings.Builder
+		for {
+			if i >= len(s) {
+				return nil, "", fmt.Errorf("label %s: unterminated value", name)
+			}
+			c := s[i]
+			if c == '"' {
+				i++
+				break
+			}
+			if c == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					c = '\n'
+				default:
+					c = s[i]
+				}
+			}
+			value.WriteByte(c)
+			i++
+		}
+		labels = append(labels, LabelPair{Name: name, Value: value.String()})
+	}
+}
+
+func parseExemplar(s string) (*Exemplar, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") {
+		return nil, fmt.Errorf("invalid exemplar %q", s)
+	}
+	labels, rest, err := parseLabels(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exemplar labels: %w", err)
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid exemplar %q", s)
+	}
+	e := &Exemplar{Labels: labels}
+	if e.Value, err = parseFloat(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid exemplar value: %w", err)
+	}
+	if len(fields) == 2 {
+		secs, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exemplar timestamp: %w", err)
+		}
+		e.Timestamp = time.Unix(0, int64(secs*1e9))
+	}
+	return e, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	switch s {
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func labelValue(labels []LabelPair, name string) string {
+	for _, l := range labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func labelsKey(labels []LabelPair) string {
+	pairs := make([]string, len(labels))
+	for i, l := range labels {
+		pairs[i] = l.Name + "\xfe" + l.Value
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\xff")
+}
+
+func unescapeHelp(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package health
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    MetricFamilies
+		wantErr string
+	}{
+		{
+			name: "prometheus text",
+			input: `# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/a \"b\"\n"} 12 1700000000250
+# TYPE queue_depth gauge
+queue_depth 5
+untyped_value -Inf
+`,
+			want: MetricFamilies{
+				{
+					Name: "http_requests_total",
+					Help: "Total number of HTTP requests",
+					Type: MetricTypeCounter,
+					Metrics: []Metric{{
+						Labels:    []LabelPair{{Name: "method", Value: "GET"}, {Name: "path", Value: "/a \"b\"\n"}},
+						Value:     12,
+						Timestamp: time.Unix(1700000000, 250000000),
+					}},
+				},
+				{
+					Name:    "queue_depth",
+					Type:    MetricTypeGauge,
+					Metrics: []Metric{{Value: 5}},
+				},
+				{
+					Name:    "untyped_value",
+					Type:    MetricTypeUntyped,
+					Metrics: []Metric{{Value: math.Inf(-1)}},
+				},
+			},
+		},
+		{
+			name: "histogram and summary",
+			input: `# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/",le="0.5"} 2
+latency_seconds_bucket{route="/",le="0.1"} 1
+latency_seconds_bucket{route="/",le="+Inf"} 3
+latency_seconds_sum{route="/"} 3.25
+latency_seconds_count{route="/"} 3
+# TYPE gc_seconds summary
+gc_seconds{quantile="0.5"} 0.1
+gc_seconds_sum 0.3
+gc_seconds_count 2
+`,
+			want: MetricFamilies{
+				{
+					Name: "latency_seconds",
+					Type: MetricTypeHistogram,
+					Metrics: []Metric{{
+						Labels: []LabelPair{{Name: "route", Value: "/"}},
+						Histogram: &HistogramValue{
+							Count: 3,
+							Sum:   3.25,
+							Buckets: []Bucket{
+								{UpperBound: 0.1, Count: 1},
+								{UpperBound: 0.5, Count: 2},
+								{UpperBound: math.Inf(1), Count: 3},
+							},
+						},
+					}},
+				},
+				{
+					Name: "gc_seconds",
+					Type: MetricTypeSummary,
+					Metrics: []Metric{{
+						Summary: &SummaryValue{
+							Count:     2,
+							Sum:       0.3,
+							Quantiles: []Quantile{{Quantile: 0.5, Value: 0.1}},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name: "openmetrics",
+			input: `# TYPE jobs counter
+# UNIT jobs jobs
+# HELP jobs Processed jobs
+jobs_total{queue="a"} 4 # {trace_id="abc"} 1 1700000100.5
+jobs_created{queue="a"} 1700000000.5
+# TYPE state unknown
+state 1 1700000000
+# EOF
+`,
+			want: MetricFamilies{
+				{
+					Name: "jobs_total",
+					Help: "Processed jobs",
+					Type: MetricTypeCounter,
+					Unit: "jobs",
+					Metrics: []Metric{{
+						Labels:  []LabelPair{{Name: "queue", Value: "a"}},
+						Value:   4,
+						Created: time.Unix(1700000000, 500000000),
+						Exemplar: &Exemplar{
+							Labels:    []LabelPair{{Name: "trace_id", Value: "abc"}},
+							Value:     1,
+							Timestamp: time.Unix(1700000100, 500000000),
+						},
+					}},
+				},
+				{
+					Name:    "state",
+					Type:    MetricTypeUntyped,
+					Metrics: []Metric{{Value: 1, Timestamp: time.Unix(1700000000, 0)}},
+				},
+			},
+		},
+		{
+			name:    "invalid value",
+			input:   "metric abc\n",
+			wantErr: "line 1: invalid value for metric",
+		},
+		{
+			name:    "unterminated labels",
+			input:   "# TYPE a gauge\na{x=\"1\" 1\n",
+			wantErr: "line 2: invalid labels for a",
+		},
+		{
+			name:    "unknown type",
+			input:   "# TYPE a weird\n",
+			wantErr: `unknown metric type "weird"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMetrics(strings.NewReader(tt.input))
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMetrics_RoundTrip(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter(MetricOpts{Name: "jobs_total", Help: "Jobs", Labels: []string{"queue"}}).
+		AddWithExemplar(2, map[string]string{"trace_id": "abc"}, "emails")
+	reg.NewHistogram(MetricOpts{Name: "job_duration_seconds", Unit: "seconds", Buckets: []float64{0.1, 1}}).
+		ObserveWithExemplar(0.5, map[string]string{"trace_id": "def"})
+
+	for _, write := range []func(*strings.Builder) error{
+		func(b *strings.Builder) error { return reg.WriteText(b) },
+		func(b *strings.Builder) error { return reg.WriteOpenMetrics(b) },
+	} {
+		var b strings.Builder
+		require.NoError(t, write(&b))
+
+		families, err := ParseMetrics(strings.NewReader(b.String()))
+		require.NoError(t, err)
+		require.Len(t, families, 2)
+
+		assert.Equal(t, "job_duration_seconds", families[0].Name)
+		assert.Equal(t, MetricTypeHistogram, families[0].Type)
+		require.Len(t, families[0].Metrics, 1)
+		assert.Equal(t, uint64(1), families[0].Metrics[0].Histogram.Count)
+		assert.Len(t, families[0].Metrics[0].Histogram.Buckets, 3)
+
+		assert.Equal(t, "jobs_total", families[1].Name)
+		assert.Equal(t, MetricTypeCounter, families[1].Type)
+		assert.Equal(t, 2.0, families[1].Metrics[0].Value)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build linux
+
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// userHZ is the kernel clock tick rate used in /proc/[pid]/stat. It is 100 on
+// every mainstream Linux architecture.
+const userHZ = 100
+
+func readProcessStats() (*processStats, error) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return nil, fmt.Errorf("reading /proc/self/stat: %w", err)
+	}
+
+	// The command name may contain spaces, so fields are counted from the
+	// closing parenthesis; fields[0] is field 3 (state) of proc(5).
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return nil, fmt.Errorf("parsing /proc/self/stat: malformed content")
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("parsing /proc/self/stat: expected at least 24 fields")
+	}
+
+	field := func(n int) float64 {
+		v, _ := strconv.ParseFloat(fields[n-3], 64)
+		return v
+	}
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	return &processStats{
+		cpuSeconds:    (field(14) + field(15)) / userHZ,
+		startTime:     bootTime + field(22)/userHZ,
+		virtualBytes:  field(23),
+		residentBytes: field(24) * float64(os.Getpagesize()),
+		openFDs:       countOpenFDs(),
+		maxFDs:        readMaxFDs(),
+	}, nil
+}
+
+func readBootTime() (float64, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return 0, fmt.Errorf("reading /proc/stat: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			return strconv.ParseFloat(fields[1], 64)
+		}
+	}
+	return 0, fmt.Errorf("parsing /proc/stat: btime not found")
+}
+
+func countOpenFDs() float64 {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return float64(len(entries))
+}
+
+func readMaxFDs() float64 {
+	f, err := os.Open("/proc/self/limits")
+	if err != nil {
+		return -1
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "Max open files") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Max open files"))
+		if len(fields) == 0 {
+			return -1
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return -1
+		}
+		return v
+	}
+	return -1
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package health
+
+import "errors"
+
+func readProcessStats() (*processStats, error) {
+	return nil, errors.New("process metrics are only available on Linux")
+}
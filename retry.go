@@ -0,0 +1,150 @@
+package health
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultMultiplier     = 2
+)
+
+// RetryPolicy configures how the client retries failed requests. Transport
+// errors, client timeouts and responses with one of RetryOnStatus are
+// retried; decode errors and cancelled contexts are not. Only idempotent
+// requests are retried, which covers every request the client makes.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It defaults to
+	// 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. It defaults to 5s. Requests
+	// whose Retry-After header asks for a longer delay are not retried.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the delay grows by after every attempt. It
+	// defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of every delay that is randomized, between 0 and
+	// 1, so that many clients do not retry in lockstep.
+	Jitter float64
+	// RetryOnStatus lists the unexpected status codes that are retried. It
+	// defaults to 429, 502, 503 and 504. Status codes an endpoint defines,
+	// such as 503 from a failing readiness probe, are never retried.
+	RetryOnStatus []int
+}
+
+// DefaultRetryPolicy returns a policy of three attempts with exponential
+// backoff from 100ms and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Multiplier:     defaultMultiplier,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy makes the client retry failed requests according to
+// policy. Retries stop as soon as the request context is done.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *client) {
+		c.retry = policy
+	}
+}
+
+var defaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// retryable reports whether a request that failed with err may be retried.
+func (p RetryPolicy) retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		retryOn := p.RetryOnStatus
+		if retryOn == nil {
+			retryOn = defaultRetryOnStatus
+		}
+		for _, code := range retryOn {
+			if statusErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var transportErr *TransportError
+	var timeoutErr *TimeoutError
+	return errors.As(err, &transportErr) || errors.As(err, &timeoutErr)
+}
+
+// delay returns how long to wait before the attempt following attempt, which
+// failed with err. The second return value is false if the server asked to
+// wait longer than MaxBackoff.
+func (p RetryPolicy) delay(attempt int, err error) (time.Duration, bool) {
+	initial, maxBackoff, multiplier := p.InitialBackoff, p.MaxBackoff, p.Multiplier
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	if multiplier < 1 {
+		multiplier = defaultMultiplier
+	}
+
+	backoff := math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(maxBackoff))
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	d := time.Duration(backoff)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if retryAfter, ok := parseRetryAfter(statusErr.Header.Get("Retry-After")); ok {
+			if retryAfter > maxBackoff {
+				return 0, false
+			}
+			if retryAfter > d {
+				d = retryAfter
+			}
+		}
+	}
+
+	return d, true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
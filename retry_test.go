@@ -0,0 +1,338 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Multiplier:     3,
+	}
+	unavailable := func(retryAfter string) error {
+		header := http.Header{}
+		if retryAfter != "" {
+			header.Set("Retry-After", retryAfter)
+		}
+		return &StatusError{StatusCode: http.StatusServiceUnavailable, Header: header}
+	}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		attempt   int
+		err       error
+		want      time.Duration
+		wantRetry bool
+	}{
+		{
+			name:      "first retry",
+			policy:    policy,
+			attempt:   1,
+			err:       &TransportError{},
+			want:      10 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "exponential",
+			policy:    policy,
+			attempt:   2,
+			err:       &TransportError{},
+			want:      30 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "capped",
+			policy:    policy,
+			attempt:   3,
+			err:       &TransportError{},
+			want:      50 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "defaults",
+			policy:    RetryPolicy{},
+			attempt:   2,
+			err:       &TransportError{},
+			want:      200 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "shorter Retry-After",
+			policy:    policy,
+			attempt:   2,
+			err:       unavailable("0"),
+			want:      30 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "Retry-After beyond max backoff",
+			policy:    policy,
+			attempt:   1,
+			err:       unavailable("120"),
+			wantRetry: false,
+		},
+		{
+			name:      "invalid Retry-After",
+			policy:    policy,
+			attempt:   1,
+			err:       unavailable("soon"),
+			want:      10 * time.Millisecond,
+			wantRetry: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.policy.delay(tt.attempt, tt.err)
+			assert.Equal(t, tt.wantRetry, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		got, ok := policy.delay(1, &TransportError{})
+		require.True(t, ok)
+		assert.GreaterOrEqual(t, got, 50*time.Millisecond)
+		assert.LessOrEqual(t, got, 100*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		err    error
+		want   bool
+	}{
+		{name: "transport error", err: &TransportError{}, want: true},
+		{name: "timeout", err: &TimeoutError{}, want: true},
+		{name: "decode error", err: &DecodeError{}, want: false},
+		{name: "bad gateway", err: &StatusError{StatusCode: http.StatusBadGateway}, want: true},
+		{name: "not found", err: &StatusError{StatusCode: http.StatusNotFound}, want: false},
+		{
+			name:   "custom status codes",
+			policy: RetryPolicy{RetryOnStatus: []int{http.StatusInternalServerError}},
+			err:    &StatusError{StatusCode: http.StatusInternalServerError},
+			want:   true,
+		},
+		{
+			name:   "custom status codes replace defaults",
+			policy: RetryPolicy{RetryOnStatus: []int{http.StatusInternalServerError}},
+			err:    &StatusError{StatusCode: http.StatusBadGateway},
+			want:   false,
+		},
+		{name: "other error", err: errors.New("creating request"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.retryable(tt.err))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	got, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, got)
+
+	got, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Zero(t, got)
+
+	got, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, got, float64(2*time.Second))
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("-1")
+	assert.False(t, ok)
+}
+
+// flakyHandler answers the first failures requests with status, or drops the
+// connection if status is 0, and serves next afterwards.
+func flakyHandler(t *testing.T, failures int32, status int, next http.Handler) (http.Handler, *atomic.Int32) {
+	var requests atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > failures {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if status == 0 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(status)
+	}), &requests
+}
+
+func TestClient_WithRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+
+	tests := []struct {
+		name         string
+		failures     int32
+		status       int
+		call         func(ctx context.Context, c Client) error
+		wantErr      bool
+		wantRequests int32
+	}{
+		{
+			name:     "connection reset",
+			failures: 2,
+			call: func(ctx context.Context, c Client) error {
+				_, err := c.GetHealth(ctx)
+				return err
+			},
+			wantRequests: 3,
+		},
+		{
+			name:     "bad gateway",
+			failures: 1,
+			status:   http.StatusBadGateway,
+			call: func(ctx context.Context, c Client) error {
+				_, err := c.GetStatus(ctx)
+				return err
+			},
+			wantRequests: 2,
+		},
+		{
+			name:     "metrics",
+			failures: 1,
+			status:   http.StatusServiceUnavailable,
+			call: func(ctx context.Context, c Client) error {
+				_, err := c.GetMetrics(ctx)
+				return err
+			},
+			wantRequests: 2,
+		},
+		{
+			name:     "attempts exhausted",
+			failures: 3,
+			status:   http.StatusGatewayTimeout,
+			call: func(ctx context.Context, c Client) error {
+				_, err := c.GetReadiness(ctx)
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:     "not retryable",
+			failures: 3,
+			status:   http.StatusInternalServerError,
+			call: func(ctx context.Context, c Client) error {
+				_, err := c.GetReadiness(ctx)
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, requests := flakyHandler(t, tt.failures, tt.status, NewHTTPHandler(bs))
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			client, err := NewClient(server.URL, WithRetryPolicy(policy))
+			require.NoError(t, err)
+
+			err = tt.call(context.Background(), client)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+	}
+}
+
+func TestClient_WithRetryPolicy_ProbeStatus(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"ready":false,"timestamp":"2024-01-06T15:04:05Z","checks":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetryPolicy(DefaultRetryPolicy()))
+	require.NoError(t, err)
+
+	resp, err := client.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, int32(1), requests.Load(), "a failing probe is an answer, not an error")
+}
+
+func TestClient_WithRetryPolicy_RetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     100 * time.Millisecond,
+	}))
+	require.NoError(t, err)
+
+	_, err = client.GetStatus(context.Background())
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.Equal(t, int32(1), requests.Load(), "Retry-After exceeds MaxBackoff")
+}
+
+func TestClient_WithRetryPolicy_ContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Minute,
+	}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.GetHealth(ctx)
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Less(t, time.Since(start), time.Second)
+}
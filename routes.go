@@ -0,0 +1,136 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultHealthPath    = "/health"
+	defaultLivenessPath  = "/health/live"
+	defaultReadinessPath = "/health/ready"
+	defaultStartupPath   = "/health/startup"
+	defaultStatusPath    = "/status"
+	defaultMetricsPath   = "/metrics"
+)
+
+// RouteOptions configures the paths of the endpoints. Empty paths keep their
+// defaults. Every path, including aliases, is mounted below Prefix.
+type RouteOptions struct {
+	Prefix    string
+	Health    string
+	Liveness  string
+	Readiness string
+	Startup   string
+	Status    string
+	Metrics   string
+	// Aliases maps additional paths to the default path of the endpoint they
+	// serve, e.g. {"/healthz": "/health/live", "/readyz": "/health/ready"}.
+	Aliases map[string]string
+}
+
+// endpointPaths lists the default path of every endpoint in registration
+// order.
+var endpointPaths = []string{
+	defaultHealthPath,
+	defaultLivenessPath,
+	defaultReadinessPath,
+	defaultStartupPath,
+	defaultStatusPath,
+	defaultMetricsPath,
+}
+
+// Validate reports aliases that target an unknown endpoint and paths, aliases
+// included, that are used by more than one endpoint.
+func (o RouteOptions) Validate() error {
+	aliases := make([]string, 0, len(o.Aliases))
+	for alias := range o.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		target := o.Aliases[alias]
+		if !isEndpointPath(target) {
+			return fmt.Errorf("alias %q targets unknown endpoint %q", alias, target)
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, e := range endpointPaths {
+		for _, p := range append([]string{o.path(e)}, o.aliases(e)...) {
+			if owner, ok := owners[p]; ok {
+				return fmt.Errorf("path %q is used by both %s and %s", p, owner, e)
+			}
+			owners[p] = e
+		}
+	}
+	return nil
+}
+
+func isEndpointPath(p string) bool {
+	for _, e := range endpointPaths {
+		if p == e {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRoutes serves the endpoints on the paths configured by routes instead
+// of the defaults. NewHTTPHandler panics if routes are not valid; see
+// RouteOptions.Validate.
+func WithRoutes(routes RouteOptions) HandlerOption {
+	return func(h *HTTPHandler) {
+		h.routeOpts = routes
+	}
+}
+
+// WithClientRoutes makes the client request the paths configured by routes.
+// Pass the same RouteOptions as the server's handler.
+func WithClientRoutes(routes RouteOptions) ClientOption {
+	return func(c *client) {
+		c.routes = routes
+	}
+}
+
+// path returns the full path of the endpoint whose default path is
+// defaultPath.
+func (o RouteOptions) path(defaultPath string) string {
+	p := map[string]string{
+		defaultHealthPath:    o.Health,
+		defaultLivenessPath:  o.Liveness,
+		defaultReadinessPath: o.Readiness,
+		defaultStartupPath:   o.Startup,
+		defaultStatusPath:    o.Status,
+		defaultMetricsPath:   o.Metrics,
+	}[defaultPath]
+	if p == "" {
+		p = defaultPath
+	}
+	return o.join(p)
+}
+
+// aliases returns the full alias paths of the endpoint whose default path is
+// defaultPath, sorted for stable registration.
+func (o RouteOptions) aliases(defaultPath string) []string {
+	var paths []string
+	for alias, target := range o.Aliases {
+		if target == defaultPath {
+			paths = append(paths, o.join(alias))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (o RouteOptions) join(p string) string {
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	prefix := strings.TrimSuffix(o.Prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix + p
+}
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteOptions_Path(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        RouteOptions
+		defaultPath string
+		want        string
+	}{
+		{
+			name:        "defaults",
+			defaultPath: defaultReadinessPath,
+			want:        "/health/ready",
+		},
+		{
+			name:        "custom path",
+			opts:        RouteOptions{Readiness: "/readyz"},
+			defaultPath: defaultReadinessPath,
+			want:        "/readyz",
+		},
+		{
+			name:        "prefix with default path",
+			opts:        RouteOptions{Prefix: "/orders/"},
+			defaultPath: defaultMetricsPath,
+			want:        "/orders/metrics",
+		},
+		{
+			name:        "prefix and custom path without slashes",
+			opts:        RouteOptions{Prefix: "orders", Health: "healthz"},
+			defaultPath: defaultHealthPath,
+			want:        "/orders/healthz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.path(tt.defaultPath))
+		})
+	}
+}
+
+func TestRouteOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    RouteOptions
+		wantErr string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "custom paths and aliases",
+			opts: RouteOptions{
+				Liveness: "/livez",
+				Aliases:  map[string]string{"/healthz": defaultLivenessPath, "/health/live": defaultLivenessPath},
+			},
+		},
+		{
+			name:    "path used by two endpoints",
+			opts:    RouteOptions{Liveness: "/health"},
+			wantErr: `path "/health" is used by both /health and /health/live`,
+		},
+		{
+			name: "alias of another endpoint's path",
+			opts: RouteOptions{
+				Liveness: "/healthz",
+				Aliases:  map[string]string{"/healthz": defaultReadinessPath},
+			},
+			wantErr: `path "/healthz" is used by both /health/live and /health/ready`,
+		},
+		{
+			name:    "alias of its own endpoint's path",
+			opts:    RouteOptions{Prefix: "/orders", Aliases: map[string]string{"/metrics": defaultMetricsPath}},
+			wantErr: `path "/orders/metrics" is used by both /metrics and /metrics`,
+		},
+		{
+			name:    "alias with unknown target",
+			opts:    RouteOptions{Aliases: map[string]string{"/readyz": "/ready"}},
+			wantErr: `alias "/readyz" targets unknown endpoint "/ready"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewHTTPHandler_InvalidRoutes(t *testing.T) {
+	routes := RouteOptions{Liveness: "/healthz", Aliases: map[string]string{"/healthz": defaultReadinessPath}}
+
+	assert.PanicsWithValue(t,
+		`health: invalid routes: path "/healthz" is used by both /health/live and /health/ready`,
+		func() { NewHTTPHandler(&mockServer{}, WithRoutes(routes)) },
+	)
+}
+
+func TestHTTPHandler_WithRoutes(t *testing.T) {
+	routes := RouteOptions{
+		Prefix:    "/orders",
+		Liveness:  "/livez",
+		Readiness: "/readyz",
+		Aliases: map[string]string{
+			"/healthz":      defaultLivenessPath,
+			"/health/ready": defaultReadinessPath,
+		},
+	}
+	handler := NewHTTPHandler(&mockServer{}, WithRoutes(routes))
+
+	chiRouter := chi.NewRouter()
+	handler.RegisterRoutes(chiRouter)
+
+	mux := http.NewServeMux()
+	handler.RegisterServeMux(mux)
+
+	routers := map[string]http.Handler{
+		"chi":       chiRouter,
+		"ServeMux":  mux,
+		"ServeHTTP": handler,
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/orders/health", wantStatus: http.StatusOK},
+		{path: "/orders/livez", wantStatus: http.StatusOK},
+		{path: "/orders/readyz", wantStatus: http.StatusOK},
+		{path: "/orders/health/startup", wantStatus: http.StatusOK},
+		{path: "/orders/status", wantStatus: http.StatusOK},
+		{path: "/orders/metrics", wantStatus: http.StatusOK},
+		{path: "/orders/healthz", wantStatus: http.StatusOK},
+		{path: "/orders/health/ready", wantStatus: http.StatusOK},
+		{path: "/orders/health/live", wantStatus: http.StatusNotFound},
+		{path: "/health", wantStatus: http.StatusNotFound},
+	}
+
+	for name, router := range routers {
+		for _, tt := range tests {
+			t.Run(name+tt.path, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+				assert.Equal(t, tt.wantStatus, rec.Code)
+			})
+		}
+	}
+}
+
+func TestClient_WithClientRoutes(t *testing.T) {
+	routes := RouteOptions{
+		Prefix:    "/orders",
+		Health:    "/healthz",
+		Liveness:  "/livez",
+		Readiness: "/readyz",
+		Startup:   "/startupz",
+		Status:    "/info",
+		Metrics:   "/prometheus",
+	}
+
+	server := httptest.NewServer(NewHTTPHandler(NewBaseServer("orders", "1.0.0", "test"), WithRoutes(routes)))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithClientRoutes(routes))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetHealth(ctx)
+	assert.NoError(t, err)
+	_, err = client.GetLiveness(ctx)
+	assert.NoError(t, err)
+	_, err = client.GetReadiness(ctx)
+	assert.NoError(t, err)
+	_, err = client.GetStartup(ctx)
+	assert.NoError(t, err)
+	status, err := client.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", status.ServiceName)
+	_, err = client.GetMetrics(ctx)
+	assert.NoError(t, err)
+
+	defaultClient, err := NewClient(server.URL)
+	require.NoError(t, err)
+	_, err = defaultClient.GetStatus(ctx)
+	assert.ErrorContains(t, err, "unexpected status code 404")
+}
@@ -3,7 +3,11 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -17,22 +21,121 @@ type Server interface {
 	GetMetrics(ctx context.Context) (string, error)
 }
 
+// StartupProber is implemented by servers that track their own startup.
+// HTTPHandler serves the startup endpoint from it; other servers are
+// reported as started as soon as they answer.
+type StartupProber interface {
+	GetStartup(ctx context.Context) (*StartupResponse, error)
+}
+
+// MetricsGatherer is implemented by servers that can return their metrics as
+// structured families. HTTPHandler uses it to serve the OpenMetrics format to
+// scrapers that ask for it; other servers are always served the Prometheus
+// text format returned by GetMetrics.
+type MetricsGatherer interface {
+	GatherMetrics(ctx context.Context) ([]MetricFamily, error)
+}
+
 type HTTPHandler struct {
-	server Server
+	server              Server
+	legacyChecks        bool
+	degradedUnavailable bool
+	routeOpts           RouteOptions
+	auth                Authenticator
+	protected           map[string]bool
+	detailAuth          Authenticator
+	redactors           []Redactor
+	mux                 *http.ServeMux
+}
+
+type HandlerOption func(*HTTPHandler)
+
+// WithLegacyChecks renders ReadinessResponse.Checks as the legacy
+// map[string]string shape for consumers that predate structured results.
+func WithLegacyChecks() HandlerOption {
+	return func(h *HTTPHandler) {
+		h.legacyChecks = true
+	}
+}
+
+// WithDegradedUnavailable makes degraded health and readiness responses return
+// 503 Service Unavailable instead of the default 200 OK.
+func WithDegradedUnavailable() HandlerOption {
+	return func(h *HTTPHandler) {
+		h.degradedUnavailable = true
+	}
 }
 
-func NewHTTPHandler(server Server) *HTTPHandler {
-	return &HTTPHandler{
+func NewHTTPHandler(server Server, opts ...HandlerOption) *HTTPHandler {
+	h := &HTTPHandler{
 		server: server,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+	if err := h.routeOpts.Validate(); err != nil {
+		panic("health: invalid routes: " + err.Error())
+	}
+
+	h.mux = http.NewServeMux()
+	h.RegisterServeMux(h.mux)
+
+	return h
+}
+
+type route struct {
+	path    string
+	handler http.HandlerFunc
+}
+
+// routes returns every path served by the handler, aliases included.
+func (h *HTTPHandler) routes() []route {
+	endpoints := []route{
+		{defaultHealthPath, h.handleGetHealth},
+		{defaultLivenessPath, h.handleGetLiveness},
+		{defaultReadinessPath, h.handleGetReadiness},
+		{defaultStartupPath, h.handleGetStartup},
+		{defaultStatusPath, h.handleGetStatus},
+		{defaultMetricsPath, h.handleGetMetrics},
+	}
+
+	var routes []route
+	for _, e := range endpoints {
+		handler, checkHandler := e.handler, http.HandlerFunc(h.handleGetReadinessCheck)
+		if h.protected[e.path] {
+			handler, checkHandler = h.requireAuth(handler), h.requireAuth(checkHandler)
+		}
+
+		paths := append([]string{h.routeOpts.path(e.path)}, h.routeOpts.aliases(e.path)...)
+		for _, p := range paths {
+			routes = append(routes, route{p, handler})
+			if e.path == defaultReadinessPath {
+				routes = append(routes, route{p + "/{" + checkPathParam + "}", checkHandler})
+			}
+		}
+	}
+	return routes
 }
 
 func (h *HTTPHandler) RegisterRoutes(r chi.Router) {
-	r.Get("/health", h.handleGetHealth)
-	r.Get("/health/live", h.handleGetLiveness)
-	r.Get("/health/ready", h.handleGetReadiness)
-	r.Get("/status", h.handleGetStatus)
-	r.Get("/metrics", h.handleGetMetrics)
+	for _, rt := range h.routes() {
+		r.Get(rt.path, rt.handler)
+	}
+}
+
+// RegisterServeMux registers the endpoints on a standard library mux using
+// method patterns such as "GET /health".
+func (h *HTTPHandler) RegisterServeMux(mux *http.ServeMux) {
+	for _, rt := range h.routes() {
+		mux.HandleFunc(http.MethodGet+" "+rt.path, rt.handler)
+	}
+}
+
+// ServeHTTP serves the endpoints directly, so the handler can be mounted on
+// any router that accepts an http.Handler. Other paths get 404.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
 }
 
 func (h *HTTPHandler) handleGetHealth(w http.ResponseWriter, r *http.Request) {
@@ -42,9 +145,15 @@ func (h *HTTPHandler) handleGetHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := http.StatusOK
-	if resp.Status == HealthStatusUnhealthy {
-		status = http.StatusServiceUnavailable
+	status := h.statusCode(resp.Status)
+
+	switch probeFormat(w, r) {
+	case ContentTypeHealthJSON:
+		h.writeHealthJSON(w, status, newHealthJSONResponse(resp.Status, nil))
+		return
+	case mediaTypeText:
+		h.writeProbeText(w, r, status, "health", nil)
+		return
 	}
 
 	h.writeJSON(w, status, resp)
@@ -61,21 +170,135 @@ func (h *HTTPHandler) handleGetLiveness(w http.ResponseWriter, r *http.Request)
 	if !resp.Alive {
 		status = http.StatusServiceUnavailable
 	}
+	resp = h.livenessView(r, resp)
+
+	switch probeFormat(w, r) {
+	case ContentTypeHealthJSON:
+		h.writeHealthJSON(w, status, newHealthJSONResponse(probeStatus(resp.Alive, resp.Checks), resp.Checks))
+		return
+	case mediaTypeText:
+		h.writeProbeText(w, r, status, "liveness", resp.Checks)
+		return
+	}
 
 	h.writeJSON(w, status, resp)
 }
 
+// checkPathParam names the wildcard of the per-check readiness endpoint.
+const checkPathParam = "check"
+
+// handleGetReadiness serves readiness, narrowed down to the checks selected
+// by the include and exclude query parameters when present.
 func (h *HTTPHandler) handleGetReadiness(w http.ResponseWriter, r *http.Request) {
-	resp, err := h.server.GetReadiness(r.Context())
+	query := r.URL.Query()
+	h.serveReadiness(w, r, CheckFilter{
+		Include: parseCheckNames(query["include"]),
+		Exclude: parseCheckNames(query["exclude"]),
+	})
+}
+
+// handleGetReadinessCheck serves the readiness of the single check named in
+// the path, or 404 if there is no such check.
+func (h *HTTPHandler) handleGetReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue(checkPathParam)
+	if name == "" {
+		name = chi.URLParam(r, checkPathParam)
+	}
+	h.serveReadiness(w, r, CheckFilter{Include: []string{name}})
+}
+
+func (h *HTTPHandler) serveReadiness(w http.ResponseWriter, r *http.Request, filter CheckFilter) {
+	if !h.fullDetail(r) {
+		// Filters would reveal which checks exist and how each one fares, so
+		// callers without full detail always get the overall readiness.
+		filter = CheckFilter{}
+	}
+
+	resp, err := h.readiness(r.Context(), filter)
 	if err != nil {
 		h.writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	status := http.StatusOK
+	if missing := filter.missing(resp.Checks); len(missing) > 0 {
+		h.writeError(w, http.StatusNotFound, fmt.Errorf("unknown checks: %s", strings.Join(missing, ", ")))
+		return
+	}
+
+	status := h.statusCode(resp.Status)
 	if !resp.Ready {
 		status = http.StatusServiceUnavailable
 	}
+	resp = h.readinessView(r, resp)
+
+	switch probeFormat(w, r) {
+	case ContentTypeHealthJSON:
+		overall := resp.Status
+		if overall == "" || !resp.Ready {
+			overall = probeStatus(resp.Ready, resp.Checks)
+		}
+		h.writeHealthJSON(w, status, newHealthJSONResponse(overall, resp.Checks))
+		return
+	case mediaTypeText:
+		h.writeProbeText(w, r, status, "readiness", resp.Checks)
+		return
+	}
+
+	if h.legacyChecks {
+		h.writeJSON(w, status, &legacyReadinessResponse{
+			Ready:     resp.Ready,
+			Timestamp: resp.Timestamp,
+			Checks:    legacyChecks(resp.Checks),
+		})
+		return
+	}
+
+	h.writeJSON(w, status, resp)
+}
+
+// readiness returns the readiness of the checks selected by filter, letting
+// the server skip the others if it implements ReadinessFilterer.
+func (h *HTTPHandler) readiness(ctx context.Context, filter CheckFilter) (*ReadinessResponse, error) {
+	if filter.empty() {
+		return h.server.GetReadiness(ctx)
+	}
+
+	if filterer, ok := h.server.(ReadinessFilterer); ok {
+		return filterer.GetReadinessFiltered(ctx, filter)
+	}
+
+	resp, err := h.server.GetReadiness(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterReadiness(resp, filter), nil
+}
+
+func (h *HTTPHandler) handleGetStartup(w http.ResponseWriter, r *http.Request) {
+	resp := &StartupResponse{Started: true, Timestamp: time.Now()}
+	var err error
+	if prober, ok := h.server.(StartupProber); ok {
+		resp, err = prober.GetStartup(r.Context())
+	}
+	if err != nil {
+		h.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !resp.Started {
+		status = http.StatusServiceUnavailable
+	}
+	resp = h.startupView(r, resp)
+
+	switch probeFormat(w, r) {
+	case ContentTypeHealthJSON:
+		h.writeHealthJSON(w, status, newHealthJSONResponse(probeStatus(resp.Started, resp.Tasks), resp.Tasks))
+		return
+	case mediaTypeText:
+		h.writeProbeText(w, r, status, "startup", resp.Tasks)
+		return
+	}
 
 	h.writeJSON(w, status, resp)
 }
@@ -87,21 +310,65 @@ func (h *HTTPHandler) handleGetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, resp)
+	h.writeJSON(w, http.StatusOK, h.statusView(r, resp))
 }
 
+const (
+	mediaTypeJSON        = "application/json"
+	mediaTypeText        = "text/plain"
+	mediaTypeOpenMetrics = "application/openmetrics-text"
+)
+
 func (h *HTTPHandler) handleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Accept")
+
+	gatherer, ok := h.server.(MetricsGatherer)
+	accept := r.Header.Get("Accept")
+	if ok && negotiateContentType(accept, []string{mediaTypeText, mediaTypeOpenMetrics}) == mediaTypeOpenMetrics {
+		families, err := gatherer.GatherMetrics(r.Context())
+		switch {
+		case errors.Is(err, errTextOnlyMetrics):
+			// Fall back to the text format below.
+		case err != nil:
+			h.writeError(w, http.StatusInternalServerError, err)
+			return
+		default:
+			var b strings.Builder
+			if err := WriteOpenMetrics(&b, families); err != nil {
+				h.writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+
+			w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(b.String()))
+			return
+		}
+	}
+
 	metrics, err := h.server.GetMetrics(r.Context())
 	if err != nil {
 		h.writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Header().Set("Content-Type", ContentTypeText)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(metrics))
 }
 
+func (h *HTTPHandler) statusCode(status HealthStatus) int {
+	switch status {
+	case HealthStatusUnhealthy:
+		return http.StatusServiceUnavailable
+	case HealthStatusDegraded:
+		if h.degradedUnavailable {
+			return http.StatusServiceUnavailable
+		}
+	}
+	return http.StatusOK
+}
+
 func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -110,14 +377,47 @@ func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, v interface{}
 	}
 }
 
+func (h *HTTPHandler) writeHealthJSON(w http.ResponseWriter, status int, resp *HealthJSONResponse) {
+	w.Header().Set("Content-Type", ContentTypeHealthJSON)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// probeFormat returns the media type to render a probe response in: plain
+// JSON unless the request prefers application/health+json or text/plain, or
+// asks for the verbose text listing with ?verbose.
+func probeFormat(w http.ResponseWriter, r *http.Request) string {
+	w.Header().Add("Vary", "Accept")
+	if r.URL.Query().Has("verbose") {
+		return mediaTypeText
+	}
+	return negotiateContentType(r.Header.Get("Accept"), []string{mediaTypeJSON, ContentTypeHealthJSON, mediaTypeText})
+}
+
+// probeStatus derives an overall status for probes that only report a
+// boolean: unhealthy when the probe fails, otherwise the aggregate of checks.
+func probeStatus(ok bool, checks map[string]CheckResult) HealthStatus {
+	if !ok {
+		return HealthStatusUnhealthy
+	}
+	if status := aggregateStatus(checks); status != HealthStatusUnhealthy {
+		return status
+	}
+	return HealthStatusDegraded
+}
+
 func (h *HTTPHandler) writeError(w http.ResponseWriter, status int, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(map[string]string{
-		"error": err.Error(),
+		"error": h.redact(err.Error()),
 	})
 }
 
+const startupCheckName = "startup"
+
 type BaseServer struct {
 	ServiceName  string
 	Version      string
@@ -128,7 +428,20 @@ type BaseServer struct {
 	Hostname     string
 	CheckFunc    func(ctx context.Context) map[string]string
 	MetricsFunc  func(ctx context.Context) (string, error)
+	Metrics      *Registry
 	Dependencies []Dependency
+
+	// HealthFromReadiness makes GetHealth report the status of the most
+	// recent unfiltered readiness probe instead of always reporting healthy.
+	// The checks are not run again, but /health then returns 503 while the
+	// service is not ready, so liveness probes must not use it.
+	HealthFromReadiness bool
+
+	checks    checkRegistry
+	checkFunc checkFuncState
+	liveness  checkRegistry
+	startup   startupState
+	readiness atomic.Value // HealthStatus of the last unfiltered readiness probe
 }
 
 func NewBaseServer(serviceName, version, environment string) *BaseServer {
@@ -137,44 +450,171 @@ func NewBaseServer(serviceName, version, environment string) *BaseServer {
 		Version:     version,
 		StartTime:   time.Now(),
 		Environment: environment,
+		Metrics:     NewRegistry(),
 	}
 }
 
+// RegisterChecker adds a check that is run on every readiness probe. A
+// checker registered under an existing name replaces the previous one.
+func (s *BaseServer) RegisterChecker(checker Checker, opts ...CheckOption) {
+	s.checks.register(checker, newCheckMetrics(s.Metrics, "readiness"), opts...)
+}
+
+// RegisterLivenessChecker adds a check that is run on every liveness probe.
+// Only critical liveness failures report the process as not alive.
+func (s *BaseServer) RegisterLivenessChecker(checker Checker, opts ...CheckOption) {
+	s.liveness.register(checker, newCheckMetrics(s.Metrics, "liveness"), opts...)
+}
+
+// NewHeartbeat registers a liveness watchdog that fails once Beat has not been
+// called for longer than maxInterval.
+func (s *BaseServer) NewHeartbeat(name string, maxInterval time.Duration) *Heartbeat {
+	hb := newHeartbeat(name, maxInterval)
+	s.liveness.register(hb, newCheckMetrics(s.Metrics, "liveness"))
+	return hb
+}
+
+// StartBackgroundChecks runs every readiness and liveness check registered
+// with WithCheckInterval in its own goroutine until ctx is cancelled. Probes
+// are served from the cached results in the meantime.
+func (s *BaseServer) StartBackgroundChecks(ctx context.Context) {
+	s.checks.start(ctx)
+	s.liveness.start(ctx)
+}
+
+// RegisterStartupTask adds a one-shot task that must succeed before the
+// server reports itself as started. Once any task is registered, readiness is
+// withheld until startup completes.
+func (s *BaseServer) RegisterStartupTask(name string, task func(ctx context.Context) error) {
+	s.startup.add(name, task)
+}
+
+// RunStartupTasks runs the registered startup tasks in registration order and
+// marks startup complete if all of them succeed. It stops at the first
+// failing task and returns its error.
+func (s *BaseServer) RunStartupTasks(ctx context.Context) error {
+	return s.startup.run(ctx)
+}
+
+// RequireStartup withholds startup, and with it readiness, until MarkStarted
+// is called, even if no startup tasks are registered. Services that perform
+// their own initialisation call it before serving probes.
+func (s *BaseServer) RequireStartup() {
+	s.startup.require()
+}
+
+// MarkStarted marks startup complete regardless of the state of the startup
+// tasks, for services that perform their own initialisation.
+func (s *BaseServer) MarkStarted() {
+	s.startup.markComplete()
+}
+
+// GetHealth reports healthy unless HealthFromReadiness is set, in which case
+// it reports the status of the most recent unfiltered readiness probe, or
+// healthy before the first one.
 func (s *BaseServer) GetHealth(ctx context.Context) (*HealthResponse, error) {
+	status := HealthStatusHealthy
+	if s.HealthFromReadiness {
+		if last, ok := s.readiness.Load().(HealthStatus); ok {
+			status = last
+		}
+	}
+
 	return &HealthResponse{
-		Status:    HealthStatusHealthy,
+		Status:    status,
 		Timestamp: time.Now(),
 	}, nil
 }
 
 func (s *BaseServer) GetLiveness(ctx context.Context) (*LivenessResponse, error) {
+	checks := s.liveness.runAll(ctx)
+	if len(checks) == 0 {
+		checks = nil
+	}
+
 	return &LivenessResponse{
-		Alive:     true,
+		Alive:     aggregateStatus(checks) != HealthStatusUnhealthy,
 		Timestamp: time.Now(),
+		Checks:    checks,
+	}, nil
+}
+
+func (s *BaseServer) GetStartup(ctx context.Context) (*StartupResponse, error) {
+	tasks := s.startup.snapshot()
+	if len(tasks) == 0 {
+		tasks = nil
+	}
+
+	return &StartupResponse{
+		Started:   s.startup.started(),
+		Timestamp: time.Now(),
+		Tasks:     tasks,
 	}, nil
 }
 
 func (s *BaseServer) GetReadiness(ctx context.Context) (*ReadinessResponse, error) {
-	checks := make(map[string]string)
-	ready := true
-
-	if s.CheckFunc != nil {
-		checks = s.CheckFunc(ctx)
-		for _, status := range checks {
-			if status != "connected" && status != "available" && status != "reachable" && status != "healthy" {
-				ready = false
-				break
-			}
+	return s.GetReadinessFiltered(ctx, CheckFilter{})
+}
+
+// GetReadinessFiltered runs only the readiness checks selected by filter and
+// derives readiness from their results. CheckFunc cannot run a subset of its
+// checks, so it is skipped entirely when the filter includes registered
+// checks only.
+func (s *BaseServer) GetReadinessFiltered(ctx context.Context, filter CheckFilter) (*ReadinessResponse, error) {
+	checks := s.runChecks(ctx, filter)
+	if !s.startup.started() && filter.matches(startupCheckName) {
+		checks[startupCheckName] = CheckResult{
+			Status:      HealthStatusUnhealthy,
+			Criticality: CriticalityCritical,
+			Message:     "startup has not completed",
 		}
 	}
+	status := aggregateStatus(checks)
+	if filter.empty() {
+		s.readiness.Store(status)
+	}
 
 	return &ReadinessResponse{
-		Ready:     ready,
-		Timestamp: time.Now(),
-		Checks:    checks,
+		Ready:        status != HealthStatusUnhealthy,
+		Status:       status,
+		Timestamp:    time.Now(),
+		Checks:       checks,
+		FailedChecks: failedChecks(checks),
 	}, nil
 }
 
+func (s *BaseServer) runChecks(ctx context.Context, filter CheckFilter) map[string]CheckResult {
+	checks := make(map[string]CheckResult)
+
+	if s.CheckFunc != nil && s.needsCheckFunc(filter) {
+		for name, result := range s.checkFunc.run(ctx, s.Metrics, s.CheckFunc) {
+			if filter.matches(name) {
+				checks[name] = result
+			}
+		}
+	}
+
+	for name, result := range s.checks.run(ctx, filter) {
+		checks[name] = result
+	}
+
+	return checks
+}
+
+// needsCheckFunc reports whether CheckFunc may return a check selected by
+// filter.
+func (s *BaseServer) needsCheckFunc(filter CheckFilter) bool {
+	if len(filter.Include) == 0 {
+		return true
+	}
+	for _, name := range filter.Include {
+		if name != startupCheckName && !s.checks.has(name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *BaseServer) GetStatus(ctx context.Context) (*StatusResponse, error) {
 	uptime := time.Since(s.StartTime).Seconds()
 
@@ -191,21 +631,47 @@ func (s *BaseServer) GetStatus(ctx context.Context) (*StatusResponse, error) {
 	}, nil
 }
 
+// GetMetrics renders the output of MetricsFunc, if set, followed by the
+// metrics of the registry and its collectors.
 func (s *BaseServer) GetMetrics(ctx context.Context) (string, error) {
+	var custom string
+	if s.MetricsFunc != nil {
+		var err error
+		custom, err = s.MetricsFunc(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if s.Metrics == nil {
+		return custom, nil
+	}
+
+	var b strings.Builder
+	if err := s.Metrics.WriteText(&b); err != nil {
+		return "", fmt.Errorf("rendering metrics: %w", err)
+	}
+	if custom != "" && b.Len() > 0 && !strings.HasSuffix(custom, "\n") {
+		custom += "\n"
+	}
+
+	return custom + b.String(), nil
+}
+
+// errTextOnlyMetrics is returned by BaseServer.GatherMetrics when MetricsFunc
+// is set: its output is already formatted text and can only be served in the
+// Prometheus text format.
+var errTextOnlyMetrics = errors.New("metrics are only available as text")
+
+// GatherMetrics returns the families of the registry and its collectors. It
+// returns errTextOnlyMetrics if MetricsFunc is set.
+func (s *BaseServer) GatherMetrics(ctx context.Context) ([]MetricFamily, error) {
 	if s.MetricsFunc != nil {
-		return s.MetricsFunc(ctx)
-	}
-
-	defaultMetrics := `# HELP http_requests_total Total number of HTTP requests
-# TYPE http_requests_total counter
-http_requests_total{method="GET",status="200"} 0
-# HELP http_request_duration_seconds HTTP request latency
-# TYPE http_request_duration_seconds histogram
-http_request_duration_seconds_bucket{le="0.005"} 0
-http_request_duration_seconds_bucket{le="0.01"} 0
-http_request_duration_seconds_bucket{le="0.025"} 0
-http_request_duration_seconds_sum 0
-http_request_duration_seconds_count 0
-`
-	return defaultMetrics, nil
+		return nil, errTextOnlyMetrics
+	}
+	if s.Metrics == nil {
+		return nil, nil
+	}
+
+	return s.Metrics.Gather(), nil
 }
@@ -7,7 +7,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,6 +22,7 @@ import (
 type mockServer struct {
 	healthFunc    func(ctx context.Context) (*HealthResponse, error)
 	livenessFunc  func(ctx context.Context) (*LivenessResponse, error)
+	startupFunc   func(ctx context.Context) (*StartupResponse, error)
 	readinessFunc func(ctx context.Context) (*ReadinessResponse, error)
 	statusFunc    func(ctx context.Context) (*StatusResponse, error)
 	metricsFunc   func(ctx context.Context) (string, error)
@@ -38,11 +42,18 @@ func (m *mockServer) GetLiveness(ctx context.Context) (*LivenessResponse, error)
 	return &LivenessResponse{Alive: true, Timestamp: time.Now()}, nil
 }
 
+func (m *mockServer) GetStartup(ctx context.Context) (*StartupResponse, error) {
+	if m.startupFunc != nil {
+		return m.startupFunc(ctx)
+	}
+	return &StartupResponse{Started: true, Timestamp: time.Now()}, nil
+}
+
 func (m *mockServer) GetReadiness(ctx context.Context) (*ReadinessResponse, error) {
 	if m.readinessFunc != nil {
 		return m.readinessFunc(ctx)
 	}
-	return &ReadinessResponse{Ready: true, Timestamp: time.Now(), Checks: map[string]string{}}, nil
+	return &ReadinessResponse{Ready: true, Timestamp: time.Now(), Checks: map[string]CheckResult{}}, nil
 }
 
 func (m *mockServer) GetStatus(ctx context.Context) (*StatusResponse, error) {
@@ -71,6 +82,7 @@ func TestHTTPHandler_handleGetHealth(t *testing.T) {
 	tests := []struct {
 		name         string
 		serverFunc   func(ctx context.Context) (*HealthResponse, error)
+		opts         []HandlerOption
 		wantStatus   int
 		wantResponse *HealthResponse
 		wantError    bool
@@ -103,6 +115,35 @@ func TestHTTPHandler_handleGetHealth(t *testing.T) {
 				Timestamp: now,
 			},
 		},
+		{
+			name: "degraded status",
+			serverFunc: func(ctx context.Context) (*HealthResponse, error) {
+				return &HealthResponse{
+					Status:    HealthStatusDegraded,
+					Timestamp: now,
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: &HealthResponse{
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+			},
+		},
+		{
+			name: "degraded status reported unavailable",
+			serverFunc: func(ctx context.Context) (*HealthResponse, error) {
+				return &HealthResponse{
+					Status:    HealthStatusDegraded,
+					Timestamp: now,
+				}, nil
+			},
+			opts:       []HandlerOption{WithDegradedUnavailable()},
+			wantStatus: http.StatusServiceUnavailable,
+			wantResponse: &HealthResponse{
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+			},
+		},
 		{
 			name: "server error",
 			serverFunc: func(ctx context.Context) (*HealthResponse, error) {
@@ -116,7 +157,7 @@ func TestHTTPHandler_handleGetHealth(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockServer{healthFunc: tt.serverFunc}
-			handler := NewHTTPHandler(mock)
+			handler := NewHTTPHandler(mock, tt.opts...)
 
 			r := chi.NewRouter()
 			handler.RegisterRoutes(r)
@@ -224,12 +265,119 @@ func TestHTTPHandler_handleGetLiveness(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_handleGetStartup(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		serverFunc   func(ctx context.Context) (*StartupResponse, error)
+		wantStatus   int
+		wantResponse *StartupResponse
+		wantError    bool
+	}{
+		{
+			name: "started",
+			serverFunc: func(ctx context.Context) (*StartupResponse, error) {
+				return &StartupResponse{
+					Started:   true,
+					Timestamp: now,
+					Tasks: map[string]CheckResult{
+						"migrations": {Status: HealthStatusHealthy},
+					},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: &StartupResponse{
+				Started:   true,
+				Timestamp: now,
+				Tasks: map[string]CheckResult{
+					"migrations": {Status: HealthStatusHealthy},
+				},
+			},
+		},
+		{
+			name: "still starting",
+			serverFunc: func(ctx context.Context) (*StartupResponse, error) {
+				return &StartupResponse{
+					Started:   false,
+					Timestamp: now,
+					Tasks: map[string]CheckResult{
+						"cache_warmup": {Status: HealthStatusUnhealthy, Message: "pending"},
+					},
+				}, nil
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantResponse: &StartupResponse{
+				Started:   false,
+				Timestamp: now,
+				Tasks: map[string]CheckResult{
+					"cache_warmup": {Status: HealthStatusUnhealthy, Message: "pending"},
+				},
+			},
+		},
+		{
+			name: "server error",
+			serverFunc: func(ctx context.Context) (*StartupResponse, error) {
+				return nil, errors.New("startup state unavailable")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockServer{startupFunc: tt.serverFunc}
+			handler := NewHTTPHandler(mock)
+
+			r := chi.NewRouter()
+			handler.RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/health/startup", nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantError {
+				var errResp map[string]string
+				err := json.NewDecoder(rec.Body).Decode(&errResp)
+				require.NoError(t, err)
+				assert.Contains(t, errResp["error"], "startup state unavailable")
+			} else {
+				var resp StartupResponse
+				err := json.NewDecoder(rec.Body).Decode(&resp)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantResponse.Started, resp.Started)
+				assert.WithinDuration(t, tt.wantResponse.Timestamp, resp.Timestamp, time.Second)
+				assert.Equal(t, tt.wantResponse.Tasks, resp.Tasks)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_handleGetStartup_WithoutStartupProber(t *testing.T) {
+	// Embedding the interface hides the GetStartup method of mockServer.
+	server := struct{ Server }{&mockServer{}}
+
+	rec := httptest.NewRecorder()
+	NewHTTPHandler(server).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/startup", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp StartupResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Started)
+	assert.WithinDuration(t, time.Now(), resp.Timestamp, time.Second)
+}
+
 func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 	now := time.Now()
 
 	tests := []struct {
 		name         string
 		serverFunc   func(ctx context.Context) (*ReadinessResponse, error)
+		opts         []HandlerOption
 		wantStatus   int
 		wantResponse *ReadinessResponse
 		wantError    bool
@@ -240,9 +388,9 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 				return &ReadinessResponse{
 					Ready:     true,
 					Timestamp: now,
-					Checks: map[string]string{
-						"database": "connected",
-						"cache":    "available",
+					Checks: map[string]CheckResult{
+						"database": {Status: HealthStatusHealthy, Message: "connected"},
+						"cache":    {Status: HealthStatusHealthy, Message: "available"},
 					},
 				}, nil
 			},
@@ -250,9 +398,9 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 			wantResponse: &ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connected",
-					"cache":    "available",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy, Message: "connected"},
+					"cache":    {Status: HealthStatusHealthy, Message: "available"},
 				},
 			},
 		},
@@ -262,9 +410,9 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 				return &ReadinessResponse{
 					Ready:     false,
 					Timestamp: now,
-					Checks: map[string]string{
-						"database": "connection failed",
-						"cache":    "timeout",
+					Checks: map[string]CheckResult{
+						"database": {Status: HealthStatusUnhealthy, Error: "connection failed"},
+						"cache":    {Status: HealthStatusUnhealthy, Error: "timeout"},
 					},
 				}, nil
 			},
@@ -272,9 +420,56 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 			wantResponse: &ReadinessResponse{
 				Ready:     false,
 				Timestamp: now,
-				Checks: map[string]string{
-					"database": "connection failed",
-					"cache":    "timeout",
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusUnhealthy, Error: "connection failed"},
+					"cache":    {Status: HealthStatusUnhealthy, Error: "timeout"},
+				},
+			},
+		},
+		{
+			name: "degraded but ready",
+			serverFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+				return &ReadinessResponse{
+					Ready:     true,
+					Status:    HealthStatusDegraded,
+					Timestamp: now,
+					Checks: map[string]CheckResult{
+						"database": {Status: HealthStatusHealthy},
+						"cache":    {Status: HealthStatusDegraded, Message: "high latency"},
+					},
+				}, nil
+			},
+			wantStatus: http.StatusOK,
+			wantResponse: &ReadinessResponse{
+				Ready:     true,
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy},
+					"cache":    {Status: HealthStatusDegraded, Message: "high latency"},
+				},
+			},
+		},
+		{
+			name: "degraded reported unavailable",
+			serverFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+				return &ReadinessResponse{
+					Ready:     true,
+					Status:    HealthStatusDegraded,
+					Timestamp: now,
+					Checks: map[string]CheckResult{
+						"cache": {Status: HealthStatusDegraded, Message: "high latency"},
+					},
+				}, nil
+			},
+			opts:       []HandlerOption{WithDegradedUnavailable()},
+			wantStatus: http.StatusServiceUnavailable,
+			wantResponse: &ReadinessResponse{
+				Ready:     true,
+				Status:    HealthStatusDegraded,
+				Timestamp: now,
+				Checks: map[string]CheckResult{
+					"cache": {Status: HealthStatusDegraded, Message: "high latency"},
 				},
 			},
 		},
@@ -284,14 +479,14 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 				return &ReadinessResponse{
 					Ready:     true,
 					Timestamp: now,
-					Checks:    map[string]string{},
+					Checks:    map[string]CheckResult{},
 				}, nil
 			},
 			wantStatus: http.StatusOK,
 			wantResponse: &ReadinessResponse{
 				Ready:     true,
 				Timestamp: now,
-				Checks:    map[string]string{},
+				Checks:    map[string]CheckResult{},
 			},
 		},
 		{
@@ -307,7 +502,7 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockServer{readinessFunc: tt.serverFunc}
-			handler := NewHTTPHandler(mock)
+			handler := NewHTTPHandler(mock, tt.opts...)
 
 			r := chi.NewRouter()
 			handler.RegisterRoutes(r)
@@ -329,6 +524,7 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 				err := json.NewDecoder(rec.Body).Decode(&resp)
 				require.NoError(t, err)
 				assert.Equal(t, tt.wantResponse.Ready, resp.Ready)
+				assert.Equal(t, tt.wantResponse.Status, resp.Status)
 				assert.WithinDuration(t, tt.wantResponse.Timestamp, resp.Timestamp, time.Second)
 				assert.Equal(t, tt.wantResponse.Checks, resp.Checks)
 			}
@@ -336,6 +532,168 @@ func TestHTTPHandler_handleGetReadiness(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_ReadinessFilter(t *testing.T) {
+	var runs sync.Map
+	newServer := func() *BaseServer {
+		bs := NewBaseServer("test-service", "1.0.0", "test")
+		for name, err := range map[string]error{
+			"database": nil,
+			"cache":    errors.New("connection refused"),
+			"queue":    nil,
+		} {
+			name, err := name, err
+			bs.RegisterChecker(NewChecker(name, func(ctx context.Context) error {
+				n, _ := runs.LoadOrStore(name, new(atomic.Int32))
+				n.(*atomic.Int32).Add(1)
+				return err
+			}))
+		}
+		return bs
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantChecks []string
+		wantError  string
+	}{
+		{
+			name:       "all checks",
+			path:       "/health/ready",
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: []string{"cache", "database", "queue"},
+		},
+		{
+			name:       "single check",
+			path:       "/health/ready/database",
+			wantStatus: http.StatusOK,
+			wantChecks: []string{"database"},
+		},
+		{
+			name:       "single failing check",
+			path:       "/health/ready/cache",
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: []string{"cache"},
+		},
+		{
+			name:       "unknown check",
+			path:       "/health/ready/billing",
+			wantStatus: http.StatusNotFound,
+			wantError:  "unknown checks: billing",
+		},
+		{
+			name:       "include",
+			path:       "/health/ready?include=database,queue",
+			wantStatus: http.StatusOK,
+			wantChecks: []string{"database", "queue"},
+		},
+		{
+			name:       "exclude",
+			path:       "/health/ready?exclude=cache",
+			wantStatus: http.StatusOK,
+			wantChecks: []string{"database", "queue"},
+		},
+		{
+			name:       "include and exclude",
+			path:       "/health/ready?include=cache&include=database&exclude=cache",
+			wantStatus: http.StatusOK,
+			wantChecks: []string{"database"},
+		},
+		{
+			name:       "unknown include",
+			path:       "/health/ready?include=database,billing",
+			wantStatus: http.StatusNotFound,
+			wantError:  "unknown checks: billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chiRouter := chi.NewRouter()
+			NewHTTPHandler(newServer()).RegisterRoutes(chiRouter)
+
+			routers := map[string]http.Handler{
+				"chi":       chiRouter,
+				"ServeHTTP": NewHTTPHandler(newServer()),
+				// mockServer does not implement ReadinessFilterer, so the
+				// handler filters the full results itself.
+				"unfiltered server": NewHTTPHandler(&mockServer{readinessFunc: newServer().GetReadiness}),
+			}
+
+			for name, router := range routers {
+				runs.Range(func(key, _ any) bool {
+					runs.Delete(key)
+					return true
+				})
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+				require.Equal(t, tt.wantStatus, rec.Code, name)
+				if tt.wantError != "" {
+					assert.Contains(t, rec.Body.String(), tt.wantError, name)
+					continue
+				}
+
+				var resp ReadinessResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), name)
+				assert.Equal(t, tt.wantChecks, mapKeys(resp.Checks), name)
+
+				if name == "unfiltered server" {
+					continue
+				}
+				var ran []string
+				runs.Range(func(key, _ any) bool {
+					ran = append(ran, key.(string))
+					return true
+				})
+				sort.Strings(ran)
+				assert.Equal(t, tt.wantChecks, ran, "%s only runs the requested checks", name)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_handleGetReadiness_LegacyChecks(t *testing.T) {
+	mock := &mockServer{
+		readinessFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+			return &ReadinessResponse{
+				Ready:     false,
+				Timestamp: time.Now(),
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy, Message: "connected"},
+					"cache":    {Status: HealthStatusHealthy},
+					"queue":    {Status: HealthStatusUnhealthy, Error: "connection refused"},
+				},
+			}, nil
+		},
+	}
+	handler := NewHTTPHandler(mock, WithLegacyChecks())
+
+	r := chi.NewRouter()
+	handler.RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/health/ready", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var resp struct {
+		Ready  bool              `json:"ready"`
+		Checks map[string]string `json:"checks"`
+	}
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, map[string]string{
+		"database": "connected",
+		"cache":    "healthy",
+		"queue":    "connection refused",
+	}, resp.Checks)
+}
+
 func TestHTTPHandler_handleGetStatus(t *testing.T) {
 	now := time.Now()
 	buildTime := now.Add(-24 * time.Hour)
@@ -525,13 +883,264 @@ http_requests_total 1234
 	}
 }
 
-func TestBaseServer_GetHealth(t *testing.T) {
+func TestHTTPHandler_handleGetMetrics_OpenMetrics(t *testing.T) {
 	server := NewBaseServer("test-service", "1.0.0", "test")
-	resp, err := server.GetHealth(context.Background())
+	server.Metrics.NewCounter(MetricOpts{Name: "jobs_total", Help: "Jobs"}).Inc()
 
-	require.NoError(t, err)
-	assert.Equal(t, HealthStatusHealthy, resp.Status)
-	assert.WithinDuration(t, time.Now(), resp.Timestamp, time.Second)
+	custom := NewBaseServer("test-service", "1.0.0", "test")
+	custom.MetricsFunc = func(ctx context.Context) (string, error) {
+		return "# TYPE custom_metric gauge\ncustom_metric 42\n", nil
+	}
+
+	tests := []struct {
+		name     string
+		server   Server
+		accept   string
+		wantType string
+		wantBody []string
+	}{
+		{
+			name:     "openmetrics requested",
+			server:   server,
+			accept:   "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5",
+			wantType: ContentTypeOpenMetrics,
+			wantBody: []string{
+				"# TYPE jobs counter\n# HELP jobs Jobs\njobs_total 1\njobs_created ",
+				"# EOF\n",
+			},
+		},
+		{
+			name:     "text preferred",
+			server:   server,
+			accept:   "text/plain;version=0.0.4,application/openmetrics-text;q=0.5",
+			wantType: ContentTypeText,
+			wantBody: []string{"# HELP jobs_total Jobs\n# TYPE jobs_total counter\njobs_total 1\n"},
+		},
+		{
+			name:     "unsupported type falls back to text",
+			server:   server,
+			accept:   "application/json",
+			wantType: ContentTypeText,
+			wantBody: []string{"jobs_total 1\n"},
+		},
+		{
+			name:     "custom text metrics fall back to text",
+			server:   custom,
+			accept:   "application/openmetrics-text",
+			wantType: ContentTypeText,
+			wantBody: []string{"custom_metric 42\n"},
+		},
+		{
+			name: "server without gatherer",
+			server: &mockServer{metricsFunc: func(ctx context.Context) (string, error) {
+				return "custom_metric 42\n", nil
+			}},
+			accept:   "application/openmetrics-text",
+			wantType: ContentTypeText,
+			wantBody: []string{"custom_metric 42\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			NewHTTPHandler(tt.server).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+			for _, want := range tt.wantBody {
+				assert.Contains(t, rec.Body.String(), want)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_HealthJSON(t *testing.T) {
+	mock := &mockServer{
+		healthFunc: func(ctx context.Context) (*HealthResponse, error) {
+			return &HealthResponse{Status: HealthStatusDegraded, Timestamp: time.Now()}, nil
+		},
+		livenessFunc: func(ctx context.Context) (*LivenessResponse, error) {
+			return &LivenessResponse{Alive: true, Timestamp: time.Now()}, nil
+		},
+		startupFunc: func(ctx context.Context) (*StartupResponse, error) {
+			return &StartupResponse{
+				Started:   false,
+				Timestamp: time.Now(),
+				Tasks:     map[string]CheckResult{"migrations": {Status: HealthStatusUnhealthy, Message: "pending"}},
+			}, nil
+		},
+		readinessFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+			return &ReadinessResponse{
+				Ready:     false,
+				Status:    HealthStatusUnhealthy,
+				Timestamp: time.Now(),
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusUnhealthy, Error: "connection refused", Measurement: "responseTime"},
+				},
+				FailedChecks: []string{"database"},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{
+			name:       "health",
+			path:       "/health",
+			accept:     "application/health+json",
+			wantStatus: http.StatusOK,
+			wantType:   ContentTypeHealthJSON,
+			wantBody:   `{"status":"warn"}`,
+		},
+		{
+			name:       "liveness",
+			path:       "/health/live",
+			accept:     "application/health+json",
+			wantStatus: http.StatusOK,
+			wantType:   ContentTypeHealthJSON,
+			wantBody:   `{"status":"pass"}`,
+		},
+		{
+			name:       "startup",
+			path:       "/health/startup",
+			accept:     "application/health+json",
+			wantStatus: http.StatusServiceUnavailable,
+			wantType:   ContentTypeHealthJSON,
+			wantBody:   `{"status":"fail","checks":{"migrations":[{"status":"fail","output":"pending"}]}}`,
+		},
+		{
+			name:       "readiness",
+			path:       "/health/ready",
+			accept:     "application/health+json, application/json;q=0.5",
+			wantStatus: http.StatusServiceUnavailable,
+			wantType:   ContentTypeHealthJSON,
+			wantBody:   `{"status":"fail","checks":{"database:responseTime":[{"status":"fail","output":"connection refused"}]}}`,
+		},
+		{
+			name:       "json preferred",
+			path:       "/health/ready",
+			accept:     "application/json, application/health+json;q=0.9",
+			wantStatus: http.StatusServiceUnavailable,
+			wantType:   "application/json",
+		},
+		{
+			name:       "wildcard keeps json",
+			path:       "/health",
+			accept:     "*/*",
+			wantStatus: http.StatusOK,
+			wantType:   "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			NewHTTPHandler(mock).RegisterRoutes(r)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantType, rec.Header().Get("Content-Type"))
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBaseServer_GetHealth(t *testing.T) {
+	tests := []struct {
+		name                string
+		checkFunc           func(ctx context.Context) map[string]string
+		healthFromReadiness bool
+		readinessProbed     bool
+		wantStatus          HealthStatus
+	}{
+		{
+			name:       "no checks",
+			wantStatus: HealthStatusHealthy,
+		},
+		{
+			name: "failing check without opt-in",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{"database": "connection refused"}
+			},
+			readinessProbed: true,
+			wantStatus:      HealthStatusHealthy,
+		},
+		{
+			name: "before first readiness probe",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{"database": "connection refused"}
+			},
+			healthFromReadiness: true,
+			wantStatus:          HealthStatusHealthy,
+		},
+		{
+			name: "degraded check",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{"database": "connected", "cache": "warn"}
+			},
+			healthFromReadiness: true,
+			readinessProbed:     true,
+			wantStatus:          HealthStatusDegraded,
+		},
+		{
+			name: "failing check",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{"database": "connection refused", "cache": "warn"}
+			},
+			healthFromReadiness: true,
+			readinessProbed:     true,
+			wantStatus:          HealthStatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			server.HealthFromReadiness = tt.healthFromReadiness
+			if tt.checkFunc != nil {
+				server.CheckFunc = func(ctx context.Context) map[string]string {
+					calls++
+					return tt.checkFunc(ctx)
+				}
+			}
+			if tt.readinessProbed {
+				_, err := server.GetReadiness(context.Background())
+				require.NoError(t, err)
+				_, err = server.GetReadinessFiltered(context.Background(), CheckFilter{Exclude: []string{"database"}})
+				require.NoError(t, err)
+				calls = 0
+			}
+
+			resp, err := server.GetHealth(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.WithinDuration(t, time.Now(), resp.Timestamp, time.Second)
+			assert.Zero(t, calls, "GetHealth must not run the checks")
+		})
+	}
 }
 
 func TestBaseServer_GetLiveness(t *testing.T) {
@@ -548,13 +1157,15 @@ func TestBaseServer_GetReadiness(t *testing.T) {
 		name       string
 		checkFunc  func(ctx context.Context) map[string]string
 		wantReady  bool
-		wantChecks map[string]string
+		wantStatus HealthStatus
+		wantChecks map[string]CheckResult
 	}{
 		{
 			name:       "no checks",
 			checkFunc:  nil,
 			wantReady:  true,
-			wantChecks: map[string]string{},
+			wantStatus: HealthStatusHealthy,
+			wantChecks: map[string]CheckResult{},
 		},
 		{
 			name: "all checks pass",
@@ -565,11 +1176,12 @@ func TestBaseServer_GetReadiness(t *testing.T) {
 					"api":      "reachable",
 				}
 			},
-			wantReady: true,
-			wantChecks: map[string]string{
-				"database": "connected",
-				"cache":    "available",
-				"api":      "reachable",
+			wantReady:  true,
+			wantStatus: HealthStatusHealthy,
+			wantChecks: map[string]CheckResult{
+				"database": {Status: HealthStatusHealthy, Message: "connected"},
+				"cache":    {Status: HealthStatusHealthy, Message: "available"},
+				"api":      {Status: HealthStatusHealthy, Message: "reachable"},
 			},
 		},
 		{
@@ -581,11 +1193,12 @@ func TestBaseServer_GetReadiness(t *testing.T) {
 					"api":      "timeout",
 				}
 			},
-			wantReady: false,
-			wantChecks: map[string]string{
-				"database": "connected",
-				"cache":    "connection failed",
-				"api":      "timeout",
+			wantReady:  false,
+			wantStatus: HealthStatusUnhealthy,
+			wantChecks: map[string]CheckResult{
+				"database": {Status: HealthStatusHealthy, Message: "connected"},
+				"cache":    {Status: HealthStatusUnhealthy, Error: "connection failed"},
+				"api":      {Status: HealthStatusUnhealthy, Error: "timeout"},
 			},
 		},
 		{
@@ -596,10 +1209,41 @@ func TestBaseServer_GetReadiness(t *testing.T) {
 					"cache":    "unavailable",
 				}
 			},
-			wantReady: false,
-			wantChecks: map[string]string{
-				"database": "connection refused",
-				"cache":    "unavailable",
+			wantReady:  false,
+			wantStatus: HealthStatusUnhealthy,
+			wantChecks: map[string]CheckResult{
+				"database": {Status: HealthStatusUnhealthy, Error: "connection refused"},
+				"cache":    {Status: HealthStatusUnhealthy, Error: "unavailable"},
+			},
+		},
+		{
+			name: "degraded check keeps service ready",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{
+					"database": "connected",
+					"cache":    "degraded",
+				}
+			},
+			wantReady:  true,
+			wantStatus: HealthStatusDegraded,
+			wantChecks: map[string]CheckResult{
+				"database": {Status: HealthStatusHealthy, Message: "connected"},
+				"cache":    {Status: HealthStatusDegraded, Message: "degraded"},
+			},
+		},
+		{
+			name: "common passing values",
+			checkFunc: func(ctx context.Context) map[string]string {
+				return map[string]string{
+					"database": "ok",
+					"cache":    "UP",
+				}
+			},
+			wantReady:  true,
+			wantStatus: HealthStatusHealthy,
+			wantChecks: map[string]CheckResult{
+				"database": {Status: HealthStatusHealthy, Message: "ok"},
+				"cache":    {Status: HealthStatusHealthy, Message: "UP"},
 			},
 		},
 	}
@@ -613,12 +1257,65 @@ func TestBaseServer_GetReadiness(t *testing.T) {
 
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantReady, resp.Ready)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			for name, result := range resp.Checks {
+				if result.Status != HealthStatusHealthy {
+					assert.Nil(t, result.LastSuccess, name)
+					continue
+				}
+				require.NotNil(t, result.LastSuccess, name)
+				assert.WithinDuration(t, time.Now(), *result.LastSuccess, time.Second, name)
+				result.LastSuccess = nil
+				resp.Checks[name] = result
+			}
 			assert.Equal(t, tt.wantChecks, resp.Checks)
 			assert.WithinDuration(t, time.Now(), resp.Timestamp, time.Second)
 		})
 	}
 }
 
+func TestBaseServer_GetReadinessFiltered(t *testing.T) {
+	var checkFuncCalls int
+	bs := NewBaseServer("test-service", "1.0.0", "test")
+	bs.CheckFunc = func(ctx context.Context) map[string]string {
+		checkFuncCalls++
+		return map[string]string{"api": "reachable", "legacy": "timeout"}
+	}
+	bs.RegisterChecker(NewChecker("database", func(ctx context.Context) error { return nil }))
+	bs.RegisterStartupTask("migrations", func(ctx context.Context) error { return nil })
+
+	resp, err := bs.GetReadinessFiltered(context.Background(), CheckFilter{Include: []string{"database"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Ready)
+	assert.Equal(t, []string{"database"}, mapKeys(resp.Checks))
+	assert.Zero(t, checkFuncCalls, "CheckFunc is skipped for registered checks")
+
+	resp, err = bs.GetReadinessFiltered(context.Background(), CheckFilter{Include: []string{"database", "api"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Ready)
+	assert.Equal(t, []string{"api", "database"}, mapKeys(resp.Checks))
+	assert.Equal(t, 1, checkFuncCalls)
+
+	resp, err = bs.GetReadinessFiltered(context.Background(), CheckFilter{Exclude: []string{"legacy"}})
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, []string{"startup"}, resp.FailedChecks)
+	assert.Equal(t, []string{"api", "database", "startup"}, mapKeys(resp.Checks))
+
+	resp, err = bs.GetReadinessFiltered(context.Background(), CheckFilter{Exclude: []string{"legacy", "startup"}})
+	require.NoError(t, err)
+	assert.True(t, resp.Ready)
+}
+
+func mapKeys(m map[string]CheckResult) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func TestBaseServer_GetStatus(t *testing.T) {
 	now := time.Now()
 	buildTime := now.Add(-24 * time.Hour)
@@ -680,22 +1377,34 @@ func TestBaseServer_GetMetrics(t *testing.T) {
 	tests := []struct {
 		name        string
 		metricsFunc func(ctx context.Context) (string, error)
+		setup       func(reg *Registry)
 		want        string
 		wantErr     bool
 	}{
 		{
-			name:        "default metrics",
+			name:        "empty registry",
 			metricsFunc: nil,
-			want: `# HELP http_requests_total Total number of HTTP requests
-# TYPE http_requests_total counter
-http_requests_total{method="GET",status="200"} 0
-# HELP http_request_duration_seconds HTTP request latency
-# TYPE http_request_duration_seconds histogram
-http_request_duration_seconds_bucket{le="0.005"} 0
-http_request_duration_seconds_bucket{le="0.01"} 0
-http_request_duration_seconds_bucket{le="0.025"} 0
-http_request_duration_seconds_sum 0
-http_request_duration_seconds_count 0
+			want:        "",
+		},
+		{
+			name: "registry metrics",
+			setup: func(reg *Registry) {
+				reg.NewCounter(MetricOpts{
+					Name:   "jobs_processed_total",
+					Help:   "Total number of processed jobs",
+					Labels: []string{"queue"},
+				}).Add(3, "emails")
+				reg.NewGauge(MetricOpts{
+					Name: "jobs_in_progress",
+					Help: "Number of jobs currently being processed",
+				}).Set(2)
+			},
+			want: `# HELP jobs_in_progress Number of jobs currently being processed
+# TYPE jobs_in_progress gauge
+jobs_in_progress 2
+# HELP jobs_processed_total Total number of processed jobs
+# TYPE jobs_processed_total counter
+jobs_processed_total{queue="emails"} 3
 `,
 		},
 		{
@@ -705,6 +1414,13 @@ http_request_duration_seconds_count 0
 			},
 			want: "# custom metrics\ncustom_metric 42\n",
 		},
+		{
+			name: "custom metrics served unchanged",
+			metricsFunc: func(ctx context.Context) (string, error) {
+				return "my_metric\t42\n", nil
+			},
+			want: "my_metric\t42\n",
+		},
 		{
 			name: "metrics error",
 			metricsFunc: func(ctx context.Context) (string, error) {
@@ -718,6 +1434,9 @@ http_request_duration_seconds_count 0
 		t.Run(tt.name, func(t *testing.T) {
 			server := NewBaseServer("test-service", "1.0.0", "test")
 			server.MetricsFunc = tt.metricsFunc
+			if tt.setup != nil {
+				tt.setup(server.Metrics)
+			}
 
 			got, err := server.GetMetrics(context.Background())
 
@@ -732,12 +1451,55 @@ http_request_duration_seconds_count 0
 	}
 }
 
+func TestBaseServer_GatherMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		metricsFunc func(ctx context.Context) (string, error)
+		wantNames   []string
+		wantErr     error
+	}{
+		{
+			name:      "registry only",
+			wantNames: []string{"jobs_total"},
+		},
+		{
+			name: "custom text metrics",
+			metricsFunc: func(ctx context.Context) (string, error) {
+				return "other 1\n", nil
+			},
+			wantErr: errTextOnlyMetrics,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			server.MetricsFunc = tt.metricsFunc
+			server.Metrics.NewCounter(MetricOpts{Name: "jobs_total", Labels: []string{"queue"}}).Inc("emails")
+
+			families, err := server.GatherMetrics(context.Background())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, f := range families {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
 func TestHTTPHandler_MethodNotAllowed(t *testing.T) {
 	handler := NewHTTPHandler(&mockServer{})
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
-	endpoints := []string{"/health", "/health/live", "/health/ready", "/status", "/metrics"}
+	endpoints := []string{"/health", "/health/live", "/health/ready", "/health/startup", "/status", "/metrics"}
 	methods := []string{http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch}
 
 	for _, endpoint := range endpoints {
@@ -754,12 +1516,55 @@ func TestHTTPHandler_MethodNotAllowed(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_StandardLibrary(t *testing.T) {
+	endpoints := []string{"/health", "/health/live", "/health/ready", "/health/startup", "/status", "/metrics"}
+
+	mux := http.NewServeMux()
+	NewHTTPHandler(&mockServer{}).RegisterServeMux(mux)
+
+	handlers := map[string]http.Handler{
+		"ServeHTTP":        NewHTTPHandler(&mockServer{}),
+		"RegisterServeMux": mux,
+	}
+
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			for _, endpoint := range endpoints {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, endpoint, nil))
+				assert.Equal(t, http.StatusOK, rec.Code, endpoint)
+
+				rec = httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, endpoint, nil))
+				assert.Equal(t, http.StatusMethodNotAllowed, rec.Code, endpoint)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/unknown", nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	}
+}
+
+func TestHTTPHandler_Mount(t *testing.T) {
+	handler := NewHTTPHandler(&mockServer{})
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
 func TestHTTPHandler_ConcurrentRequests(t *testing.T) {
 	handler := NewHTTPHandler(&mockServer{})
 	r := chi.NewRouter()
 	handler.RegisterRoutes(r)
 
-	endpoints := []string{"/health", "/health/live", "/health/ready", "/status", "/metrics"}
+	endpoints := []string{"/health", "/health/live", "/health/ready", "/health/startup", "/status", "/metrics"}
 
 	for i := 0; i < 10; i++ {
 		for _, endpoint := range endpoints {
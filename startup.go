@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type startupTask struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type startupState struct {
+	mu       sync.RWMutex
+	tasks    []startupTask
+	results  map[string]CheckResult
+	complete bool
+	required bool
+}
+
+// started reports whether startup has finished. A server without startup
+// tasks is considered started unless startup was required explicitly.
+func (s *startupState) started() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.complete || (len(s.tasks) == 0 && !s.required)
+}
+
+func (s *startupState) require() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.required = true
+}
+
+func (s *startupState) add(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, startupTask{name: name, fn: fn})
+}
+
+func (s *startupState) markComplete() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.complete = true
+}
+
+func (s *startupState) run(ctx context.Context) error {
+	s.mu.RLock()
+	tasks := make([]startupTask, len(s.tasks))
+	copy(tasks, s.tasks)
+	s.mu.RUnlock()
+
+	for _, task := range tasks {
+		start := time.Now()
+		err := task.fn(ctx)
+		result := CheckResult{
+			Status:    HealthStatusHealthy,
+			Duration:  time.Since(start),
+			CheckedAt: &start,
+		}
+		if err != nil {
+			result.Status = HealthStatusUnhealthy
+			result.Error = err.Error()
+		}
+
+		s.mu.Lock()
+		if s.results == nil {
+			s.results = make(map[string]CheckResult)
+		}
+		s.results[task.name] = result
+		s.mu.Unlock()
+
+		if err != nil {
+			return fmt.Errorf("startup task %s: %w", task.name, err)
+		}
+	}
+
+	s.markComplete()
+	return nil
+}
+
+func (s *startupState) snapshot() map[string]CheckResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := make(map[string]CheckResult, len(s.tasks))
+	for _, task := range s.tasks {
+		result, ok := s.results[task.name]
+		if !ok {
+			result = CheckResult{Status: HealthStatusUnhealthy, Message: "pending"}
+		}
+		results[task.name] = result
+	}
+	return results
+}
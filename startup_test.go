@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseServer_GetStartup(t *testing.T) {
+	tests := []struct {
+		name        string
+		tasks       map[string]func(ctx context.Context) error
+		require     bool
+		run         bool
+		markStarted bool
+		wantErr     string
+		wantStarted bool
+		wantTasks   map[string]HealthStatus
+	}{
+		{
+			name:        "no tasks",
+			wantStarted: true,
+		},
+		{
+			name:        "startup required without tasks",
+			require:     true,
+			wantStarted: false,
+		},
+		{
+			name:        "startup required and marked started",
+			require:     true,
+			markStarted: true,
+			wantStarted: true,
+		},
+		{
+			name: "tasks not run yet",
+			tasks: map[string]func(ctx context.Context) error{
+				"migrations": func(ctx context.Context) error { return nil },
+			},
+			wantStarted: false,
+			wantTasks:   map[string]HealthStatus{"migrations": HealthStatusUnhealthy},
+		},
+		{
+			name: "tasks succeed",
+			tasks: map[string]func(ctx context.Context) error{
+				"migrations":   func(ctx context.Context) error { return nil },
+				"cache_warmup": func(ctx context.Context) error { return nil },
+			},
+			run:         true,
+			wantStarted: true,
+			wantTasks: map[string]HealthStatus{
+				"migrations":   HealthStatusHealthy,
+				"cache_warmup": HealthStatusHealthy,
+			},
+		},
+		{
+			name: "task fails",
+			tasks: map[string]func(ctx context.Context) error{
+				"migrations": func(ctx context.Context) error { return errors.New("lock held") },
+			},
+			run:         true,
+			wantErr:     "startup task migrations: lock held",
+			wantStarted: false,
+			wantTasks:   map[string]HealthStatus{"migrations": HealthStatusUnhealthy},
+		},
+		{
+			name: "marked started manually",
+			tasks: map[string]func(ctx context.Context) error{
+				"migrations": func(ctx context.Context) error { return nil },
+			},
+			markStarted: true,
+			wantStarted: true,
+			wantTasks:   map[string]HealthStatus{"migrations": HealthStatusUnhealthy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewBaseServer("test-service", "1.0.0", "test")
+			for name, task := range tt.tasks {
+				server.RegisterStartupTask(name, task)
+			}
+			if tt.require {
+				server.RequireStartup()
+			}
+
+			if tt.run {
+				err := server.RunStartupTasks(context.Background())
+				if tt.wantErr != "" {
+					require.EqualError(t, err, tt.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+			}
+			if tt.markStarted {
+				server.MarkStarted()
+			}
+
+			resp, err := server.GetStartup(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStarted, resp.Started)
+
+			var gotTasks map[string]HealthStatus
+			for name, result := range resp.Tasks {
+				if gotTasks == nil {
+					gotTasks = make(map[string]HealthStatus)
+				}
+				gotTasks[name] = result.Status
+			}
+			assert.Equal(t, tt.wantTasks, gotTasks)
+		})
+	}
+}
+
+func TestBaseServer_StartupGatesReadiness(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RegisterStartupTask("migrations", func(ctx context.Context) error { return nil })
+
+	resp, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, resp.Ready)
+	assert.Equal(t, []string{"startup"}, resp.FailedChecks)
+
+	require.NoError(t, server.RunStartupTasks(context.Background()))
+
+	resp, err = server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.True(t, resp.Ready)
+	assert.NotContains(t, resp.Checks, "startup")
+}
+
+func TestBaseServer_RequireStartup(t *testing.T) {
+	server := NewBaseServer("test-service", "1.0.0", "test")
+	server.RequireStartup()
+
+	startup, err := server.GetStartup(context.Background())
+	require.NoError(t, err)
+	assert.False(t, startup.Started)
+
+	readiness, err := server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, []string{"startup"}, readiness.FailedChecks)
+
+	server.MarkStarted()
+
+	startup, err = server.GetStartup(context.Background())
+	require.NoError(t, err)
+	assert.True(t, startup.Started)
+
+	readiness, err = server.GetReadiness(context.Background())
+	require.NoError(t, err)
+	assert.True(t, readiness.Ready)
+}
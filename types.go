@@ -1,6 +1,9 @@
 package health
 
 import (
+	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -8,25 +11,195 @@ type HealthStatus string
 
 const (
 	HealthStatusHealthy   HealthStatus = "healthy"
+	HealthStatusDegraded  HealthStatus = "degraded"
 	HealthStatusUnhealthy HealthStatus = "unhealthy"
 )
 
+// worseThan reports whether s is a more severe state than other.
+func (s HealthStatus) worseThan(other HealthStatus) bool {
+	return s.severity() > other.severity()
+}
+
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthStatusHealthy:
+		return 0
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Criticality controls how a failing check affects the aggregated status.
+type Criticality string
+
+const (
+	// CriticalityCritical checks make the service unhealthy and unready.
+	CriticalityCritical Criticality = "critical"
+	// CriticalityNonCritical checks can only degrade the service.
+	CriticalityNonCritical Criticality = "non_critical"
+	// CriticalityInformational checks are reported but never affect status.
+	CriticalityInformational Criticality = "informational"
+)
+
 type HealthResponse struct {
-	Status    HealthStatus `json:"status"`
-	Timestamp time.Time    `json:"timestamp"`
+	Status     HealthStatus `json:"status"`
+	Timestamp  time.Time    `json:"timestamp"`
+	StatusCode int          `json:"-"`
 }
 
 type LivenessResponse struct {
-	Alive     bool      `json:"alive"`
-	Timestamp time.Time `json:"timestamp"`
+	Alive      bool                   `json:"alive"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Checks     map[string]CheckResult `json:"checks,omitempty"`
+	StatusCode int                    `json:"-"`
+}
+
+type StartupResponse struct {
+	Started    bool                   `json:"started"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Tasks      map[string]CheckResult `json:"tasks,omitempty"`
+	StatusCode int                    `json:"-"`
 }
 
 type ReadinessResponse struct {
+	Ready        bool                   `json:"ready"`
+	Status       HealthStatus           `json:"status,omitempty"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Checks       map[string]CheckResult `json:"checks"`
+	FailedChecks []string               `json:"failed_checks,omitempty"`
+	StatusCode   int                    `json:"-"`
+}
+
+type legacyReadinessResponse struct {
 	Ready     bool              `json:"ready"`
 	Timestamp time.Time         `json:"timestamp"`
 	Checks    map[string]string `json:"checks"`
 }
 
+type CheckResult struct {
+	Status        HealthStatus  `json:"status"`
+	Criticality   Criticality   `json:"criticality,omitempty"`
+	Message       string        `json:"message,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration_ns"`
+	CheckedAt     *time.Time    `json:"checked_at,omitempty"`
+	LastSuccess   *time.Time    `json:"last_success,omitempty"`
+	ObservedValue interface{}   `json:"observed_value,omitempty"`
+	ObservedUnit  string        `json:"observed_unit,omitempty"`
+	// Measurement, ComponentType and AffectedEndpoints describe the check in
+	// application/health+json responses, where it is keyed by
+	// "<name>:<measurement>".
+	Measurement       string   `json:"measurement,omitempty"`
+	ComponentType     string   `json:"component_type,omitempty"`
+	AffectedEndpoints []string `json:"affected_endpoints,omitempty"`
+}
+
+// UnmarshalJSON accepts both the structured form and the legacy plain string
+// form produced by older servers.
+func (r *CheckResult) UnmarshalJSON(data []byte) error {
+	var legacy string
+	if err := json.Unmarshal(data, &legacy); err == nil {
+		*r = legacyCheckResult(legacy)
+		return nil
+	}
+
+	type plain CheckResult
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// LegacyValue renders the result as the plain string used by the legacy
+// map[string]string checks shape.
+func (r CheckResult) LegacyValue() string {
+	switch {
+	case r.Status == HealthStatusHealthy && r.Message != "":
+		return r.Message
+	case r.Status == HealthStatusHealthy:
+		return string(HealthStatusHealthy)
+	case r.Error != "":
+		return r.Error
+	case r.Message != "":
+		return r.Message
+	default:
+		return string(r.Status)
+	}
+}
+
+var legacyDegradedValues = map[string]bool{
+	"degraded": true,
+	"warn":     true,
+}
+
+var legacyPassingValues = map[string]bool{
+	"connected": true,
+	"available": true,
+	"reachable": true,
+	"healthy":   true,
+	"ok":        true,
+	"up":        true,
+	"pass":      true,
+	"ready":     true,
+}
+
+func legacyCheckResult(value string) CheckResult {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if legacyPassingValues[normalized] {
+		return CheckResult{Status: HealthStatusHealthy, Message: value}
+	}
+	if legacyDegradedValues[normalized] {
+		return CheckResult{Status: HealthStatusDegraded, Message: value}
+	}
+	return CheckResult{Status: HealthStatusUnhealthy, Error: value}
+}
+
+// effectiveStatus is the contribution of the result to the aggregated status
+// once its criticality is taken into account. Results without a criticality
+// are treated as critical.
+func (r CheckResult) effectiveStatus() HealthStatus {
+	switch r.Criticality {
+	case CriticalityInformational:
+		return HealthStatusHealthy
+	case CriticalityNonCritical:
+		if r.Status == HealthStatusUnhealthy {
+			return HealthStatusDegraded
+		}
+	}
+	return r.Status
+}
+
+// aggregateStatus returns the most severe effective status among checks.
+func aggregateStatus(checks map[string]CheckResult) HealthStatus {
+	status := HealthStatusHealthy
+	for _, result := range checks {
+		if result.effectiveStatus().worseThan(status) {
+			status = result.effectiveStatus()
+		}
+	}
+	return status
+}
+
+// failedChecks returns the sorted names of the checks that make the service
+// unhealthy.
+func failedChecks(checks map[string]CheckResult) []string {
+	var failed []string
+	for name, result := range checks {
+		if result.effectiveStatus() == HealthStatusUnhealthy {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+func legacyChecks(checks map[string]CheckResult) map[string]string {
+	legacy := make(map[string]string, len(checks))
+	for name, result := range checks {
+		legacy[name] = result.LegacyValue()
+	}
+	return legacy
+}
+
 type StatusResponse struct {
 	ServiceName   string       `json:"service_name"`
 	Version       string       `json:"version"`
@@ -37,6 +210,7 @@ type StatusResponse struct {
 	Environment   string       `json:"environment"`
 	Hostname      string       `json:"hostname,omitempty"`
 	Dependencies  []Dependency `json:"dependencies,omitempty"`
+	StatusCode    int          `json:"-"`
 }
 
 type Dependency struct {
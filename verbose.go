@@ -0,0 +1,76 @@
+package health
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// writeProbeText renders a probe result as plain text in the style of the
+// Kubernetes apiserver's /healthz endpoints:
+//
+//	[+]database ok
+//	[-]cache failed: connection refused
+//	readiness check failed
+//
+// Passing probes only print "ok" unless the request asks for ?verbose.
+func (h *HTTPHandler) writeProbeText(w http.ResponseWriter, r *http.Request, status int, probe string, checks map[string]CheckResult) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	if status == http.StatusOK && !r.URL.Query().Has("verbose") {
+		_, _ = w.Write([]byte("ok\n"))
+		return
+	}
+
+	_, _ = w.Write([]byte(probeText(status == http.StatusOK, probe, checks)))
+}
+
+// probeText lists checks sorted by name followed by the overall outcome of
+// probe.
+func probeText(passed bool, probe string, checks map[string]CheckResult) string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(checkLine(name, checks[name]))
+		b.WriteByte('\n')
+	}
+
+	b.WriteString(probe)
+	if passed {
+		b.WriteString(" check passed\n")
+	} else {
+		b.WriteString(" check failed\n")
+	}
+	return b.String()
+}
+
+func checkLine(name string, result CheckResult) string {
+	reason := result.Error
+	if reason == "" {
+		reason = result.Message
+	}
+
+	switch result.Status {
+	case HealthStatusHealthy:
+		return "[+]" + name + " ok"
+	case HealthStatusDegraded:
+		return "[~]" + name + " degraded" + withReason(reason)
+	default:
+		return "[-]" + name + " failed" + withReason(reason)
+	}
+}
+
+func withReason(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	// Keep one line per check.
+	return ": " + strings.Join(strings.Fields(reason), " ")
+}
@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeText(t *testing.T) {
+	checks := map[string]CheckResult{
+		"database": {Status: HealthStatusHealthy, Message: "connected"},
+		"cache":    {Status: HealthStatusUnhealthy, Error: "dial tcp 10.0.0.1:6379:\nconnection refused"},
+		"search":   {Status: HealthStatusDegraded, Message: "slow responses"},
+		"queue":    {Status: HealthStatusUnhealthy},
+	}
+
+	want := "[-]cache failed: dial tcp 10.0.0.1:6379: connection refused\n" +
+		"[+]database ok\n" +
+		"[-]queue failed\n" +
+		"[~]search degraded: slow responses\n" +
+		"readiness check failed\n"
+	assert.Equal(t, want, probeText(false, "readiness", checks))
+
+	assert.Equal(t, "startup check passed\n", probeText(true, "startup", nil))
+}
+
+func TestHTTPHandler_ProbeText(t *testing.T) {
+	failing := &mockServer{
+		readinessFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+			return &ReadinessResponse{
+				Ready:     false,
+				Status:    HealthStatusUnhealthy,
+				Timestamp: time.Now(),
+				Checks: map[string]CheckResult{
+					"database": {Status: HealthStatusHealthy},
+					"cache":    {Status: HealthStatusUnhealthy, Error: "connection refused"},
+				},
+			}, nil
+		},
+	}
+	passing := &mockServer{
+		readinessFunc: func(ctx context.Context) (*ReadinessResponse, error) {
+			return &ReadinessResponse{
+				Ready:     true,
+				Status:    HealthStatusHealthy,
+				Timestamp: time.Now(),
+				Checks:    map[string]CheckResult{"database": {Status: HealthStatusHealthy}},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		server     Server
+		path       string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{
+			name:       "verbose passing",
+			server:     passing,
+			path:       "/health/ready?verbose",
+			wantStatus: http.StatusOK,
+			wantBody:   "[+]database ok\nreadiness check passed\n",
+		},
+		{
+			name:       "verbose overrides JSON accept",
+			server:     passing,
+			path:       "/health/ready?verbose=1",
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+			wantBody:   "[+]database ok\nreadiness check passed\n",
+		},
+		{
+			name:       "text passing",
+			server:     passing,
+			path:       "/health/ready",
+			accept:     "text/plain",
+			wantStatus: http.StatusOK,
+			wantBody:   "ok\n",
+		},
+		{
+			name:       "text failing lists checks",
+			server:     failing,
+			path:       "/health/ready",
+			accept:     "text/plain",
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "[-]cache failed: connection refused\n[+]database ok\nreadiness check failed\n",
+		},
+		{
+			name:       "single check",
+			server:     failing,
+			path:       "/health/ready/database?verbose",
+			wantStatus: http.StatusOK,
+			wantBody:   "[+]database ok\nreadiness check passed\n",
+		},
+		{
+			name:       "liveness",
+			server:     &mockServer{},
+			path:       "/health/live?verbose",
+			wantStatus: http.StatusOK,
+			wantBody:   "liveness check passed\n",
+		},
+		{
+			name:       "startup",
+			server:     &mockServer{},
+			path:       "/health/startup",
+			accept:     "text/plain",
+			wantStatus: http.StatusOK,
+			wantBody:   "ok\n",
+		},
+		{
+			name:       "health",
+			server:     &mockServer{},
+			path:       "/health?verbose",
+			wantStatus: http.StatusOK,
+			wantBody:   "health check passed\n",
+		},
+		{
+			name:       "wildcard accept gets JSON",
+			server:     passing,
+			path:       "/health/ready",
+			accept:     "*/*",
+			wantStatus: http.StatusOK,
+			wantType:   "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			NewHTTPHandler(tt.server).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantType != "" {
+				assert.Equal(t, tt.wantType, rec.Header().Get("Content-Type"))
+				return
+			}
+			assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}